@@ -0,0 +1,86 @@
+// Package authors matches commits against author patterns, on the author
+// line and Co-authored-by trailers alike, for every VCS backend.
+package authors
+
+import "strings"
+
+// Resolve lower-cases the author patterns and replaces "me" with the
+// identity returned by me, dropping it when that is empty. An identity in
+// "Name <email>" form (such as hg's ui.username) is reduced to its email.
+// me is called at most once.
+func Resolve(patterns []string, me func() string) []string {
+	var out []string
+	self, resolved := "", false
+	for _, a := range patterns {
+		if strings.EqualFold(a, "me") {
+			if !resolved {
+				self, resolved = me(), true
+				if email := identEmail(self); email != "" {
+					self = email
+				}
+			}
+			a = self
+		}
+		if a != "" {
+			out = append(out, strings.ToLower(a))
+		}
+	}
+	return out
+}
+
+// CountMatching counts the commits in output, a sequence of NUL-terminated
+// "Name <email>" and raw message pairs, whose author or any Co-authored-by
+// trailer matches one of the lower-cased patterns (see match). With no
+// patterns every commit counts.
+func CountMatching(output []byte, patterns []string) int {
+	fields := strings.Split(string(output), "\x00")
+	count := 0
+	for i := 0; i+1 < len(fields); i += 2 {
+		author := strings.TrimLeft(fields[i], "\n")
+		if len(patterns) == 0 || match(author, fields[i+1], patterns) {
+			count++
+		}
+	}
+	return count
+}
+
+// match reports whether the author line or any Co-authored-by trailer in
+// body matches one of the lower-cased patterns: exactly on the email for
+// patterns containing "@", and as a substring of "Name <email>" otherwise.
+func match(author, body string, patterns []string) bool {
+	idents := []string{author}
+	for _, line := range strings.Split(body, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "co-authored-by") {
+			idents = append(idents, value)
+		}
+	}
+	for _, ident := range idents {
+		ident = strings.ToLower(ident)
+		email := identEmail(ident)
+		for _, p := range patterns {
+			if strings.Contains(p, "@") {
+				if email == p {
+					return true
+				}
+			} else if strings.Contains(ident, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// identEmail returns the address between <> in a "Name <email>" identity,
+// or "" when there is none.
+func identEmail(ident string) string {
+	_, rest, ok := strings.Cut(ident, "<")
+	if !ok {
+		return ""
+	}
+	email, _, ok := strings.Cut(rest, ">")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(email)
+}
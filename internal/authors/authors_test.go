@@ -0,0 +1,60 @@
+package authors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	calls := 0
+	me := func() string { calls++; return "Me@Example.com" }
+
+	got := Resolve([]string{"me", "Bob", "ME"}, me)
+	want := []string{"me@example.com", "bob", "me@example.com"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if calls != 1 {
+		t.Errorf("expected me to be resolved once, got %d calls", calls)
+	}
+
+	hg := func() string { return "Al <Al@X.com>" }
+	if got := Resolve([]string{"me"}, hg); len(got) != 1 || got[0] != "al@x.com" {
+		t.Errorf("expected a Name <email> identity to reduce to its email, got %q", got)
+	}
+
+	if got := Resolve([]string{"me"}, func() string { return "" }); len(got) != 0 {
+		t.Errorf("expected unresolved me to be dropped, got %q", got)
+	}
+}
+
+func TestCountMatching(t *testing.T) {
+	output := []byte("Alice <a@x>\x00one\n\x00\nBob <b@x>\x00two\n\nCo-authored-by: Carol <c@x>\n\x00")
+
+	if got := CountMatching(output, nil); got != 2 {
+		t.Errorf("expected 2 commits without patterns, got %d", got)
+	}
+	if got := CountMatching(output, []string{"carol"}); got != 1 {
+		t.Errorf("expected 1 commit co-authored by carol, got %d", got)
+	}
+	if got := CountMatching(nil, nil); got != 0 {
+		t.Errorf("expected 0 for empty output, got %d", got)
+	}
+}
+
+func TestCountMatching_EmailsMatchExactly(t *testing.T) {
+	output := []byte("Al <al@x.com>\x00one\n\x00" +
+		"Hal <hal@x.com>\x00two\n\x00" +
+		"Sal <sal@x.com>\x00three\n\nCo-authored-by: Al <AL@x.com>\n\x00")
+
+	if got := CountMatching(output, []string{"al@x.com"}); got != 2 {
+		t.Errorf("expected al@x.com to match only Al's commits, got %d", got)
+	}
+	// Name patterns still match as substrings.
+	if got := CountMatching(output, []string{"al"}); got != 3 {
+		t.Errorf("expected the name pattern to match all three, got %d", got)
+	}
+	if got := CountMatching(output, []string{"x.com"}); got != 3 {
+		t.Errorf("expected a pattern without @ to match as a substring, got %d", got)
+	}
+}
@@ -24,7 +24,24 @@ func (c *Cache) Set(key string, value []byte, ttl time.Duration) error {
 		return fmt.Errorf("mkdir failed: %w", err)
 	}
 
-	if err := os.WriteFile(path, value, 0644); err != nil {
+	// Write to a temp file and rename it into place, so concurrent statusline
+	// processes never read a half-written value.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write failed: %w", err)
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
 		return fmt.Errorf("write failed: %w", err)
 	}
 
@@ -132,3 +132,78 @@ func TestCache_GetExpired(t *testing.T) {
 		t.Fatal("expected error for expired cache, got nil")
 	}
 }
+
+func TestLastGood_SaveLoad(t *testing.T) {
+	c := New(t.TempDir())
+	lg := NewLastGood(c, "session-1:/repo")
+
+	if _, ok := lg.Load("bedrock_model"); ok {
+		t.Fatal("expected no value before Save")
+	}
+
+	lg.Save("bedrock_model", "Claude Opus 4.6")
+	got, ok := lg.Load("bedrock_model")
+	if !ok || got != "Claude Opus 4.6" {
+		t.Errorf("expected saved value, got %q (ok=%v)", got, ok)
+	}
+}
+
+func TestLastGood_SaveSkipsUnchangedOutput(t *testing.T) {
+	c := New(t.TempDir())
+	lg := NewLastGood(c, "session-1:/repo")
+	path := c.path(lg.key("repo_info"))
+
+	lg.Save("repo_info", "main")
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(path, old, old)
+
+	lg.Save("repo_info", "main")
+	if info, _ := os.Stat(path); !info.ModTime().Equal(old) {
+		t.Errorf("expected unchanged output not to be rewritten, mtime %v", info.ModTime())
+	}
+
+	// A value close to expiring is written again even when unchanged.
+	older := time.Now().Add(-lastGoodRewrite - time.Minute)
+	_ = os.Chtimes(path, older, older)
+	lg.Save("repo_info", "main")
+	if info, _ := os.Stat(path); info.ModTime().Equal(older) {
+		t.Error("expected an aging value to be rewritten")
+	}
+
+	lg.Save("repo_info", "feature")
+	if got, _ := lg.Load("repo_info"); got != "feature" {
+		t.Errorf("expected changed output to be saved, got %q", got)
+	}
+}
+
+func TestLastGood_ScopesAreIsolated(t *testing.T) {
+	c := New(t.TempDir())
+	NewLastGood(c, "session-1:/repo-a").Save("repo_info", "repo-a")
+
+	if got, ok := NewLastGood(c, "session-2:/repo-b").Load("repo_info"); ok {
+		t.Errorf("expected other scope to miss, got %q", got)
+	}
+}
+
+func TestCache_Set_OverwritesWithoutLeavingTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir)
+
+	_ = c.Set("key", []byte("first"), time.Hour)
+	if err := c.Set("key", []byte("second"), time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	got, _ := c.Get("key", time.Hour)
+	if string(got) != "second" {
+		t.Errorf("expected second, got %s", got)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the cache file, got %d entries", len(entries))
+	}
+	info, _ := os.Stat(c.path("key"))
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("expected mode 0644, got %v", info.Mode().Perm())
+	}
+}
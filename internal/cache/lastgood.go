@@ -0,0 +1,50 @@
+package cache
+
+import "time"
+
+// lastGoodTTL bounds how old a fallback value may be before it is no longer
+// shown. Anything older is more misleading than an empty segment.
+const lastGoodTTL = 24 * time.Hour
+
+// lastGoodRewrite is how old an unchanged value may get before Save writes it
+// again, keeping it well inside lastGoodTTL.
+const lastGoodRewrite = lastGoodTTL / 2
+
+// LastGood stores the most recent successful output of each component so a
+// render that misses its deadline can show the previous value instead of
+// nothing. Entries are scoped (typically by session and workspace) so one
+// project's repo status never leaks into another's statusline.
+type LastGood struct {
+	cache *Cache
+	scope string
+}
+
+// NewLastGood creates a LastGood store backed by c and scoped by scope.
+func NewLastGood(c *Cache, scope string) *LastGood {
+	return &LastGood{cache: c, scope: scope}
+}
+
+// Load returns the last saved output for the named component.
+func (l *LastGood) Load(name string) (string, bool) {
+	data, err := l.cache.Get(l.key(name), lastGoodTTL)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Save records output as the latest value for the named component. Empty
+// output is saved too, so a component that stops rendering clears its fallback.
+// Nothing is written when the stored value already matches and is recent, so
+// a component whose output has not changed costs a read, not a write.
+func (l *LastGood) Save(name, output string) {
+	key := l.key(name)
+	if data, err := l.cache.Get(key, lastGoodRewrite); err == nil && string(data) == output {
+		return
+	}
+	_ = l.cache.Set(key, []byte(output), lastGoodTTL)
+}
+
+func (l *LastGood) key(name string) string {
+	return "last-good:v1:" + l.scope + ":" + name
+}
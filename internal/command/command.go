@@ -0,0 +1,58 @@
+// Package command runs the version control binaries (git, jj, hg) under one
+// shared timeout and error convention.
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTimeout is returned (wrapped) when a command is killed for running
+// longer than the timeout.
+var ErrTimeout = errors.New("command timed out")
+
+// DefaultTimeout bounds each command unless SetTimeout says otherwise.
+const DefaultTimeout = 2 * time.Second
+
+var timeout atomic.Int64
+
+func init() {
+	timeout.Store(int64(DefaultTimeout))
+}
+
+// SetTimeout sets the deadline applied to every command Run starts. Zero or
+// negative disables the deadline.
+func SetTimeout(d time.Duration) {
+	timeout.Store(int64(d))
+}
+
+// Run executes tool with args in dir under the timeout and returns its
+// stdout. Failures include the tool's stderr; a killed command wraps
+// ErrTimeout so callers can tell "slow" apart from "failed".
+func Run(dir, tool string, args ...string) ([]byte, error) {
+	ctx := context.Background()
+	if d := time.Duration(timeout.Load()); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, tool, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s %s: %w", tool, args[0], ErrTimeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w: %s", tool, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
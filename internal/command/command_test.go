@@ -0,0 +1,29 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	out, err := Run(t.TempDir(), "sh", "-c", "echo out; echo err >&2")
+	if err != nil || string(out) != "out\n" {
+		t.Fatalf("expected stdout only, got %q, %v", out, err)
+	}
+
+	_, err = Run(t.TempDir(), "sh", "-c", "echo broken >&2; exit 1")
+	if err == nil || !strings.Contains(err.Error(), "sh -c failed") || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected the tool and its stderr in the error, got %v", err)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	SetTimeout(50 * time.Millisecond)
+	t.Cleanup(func() { SetTimeout(DefaultTimeout) })
+
+	if _, err := Run(t.TempDir(), "sleep", "5"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
@@ -9,3 +9,12 @@ type Component interface {
 	Name() string
 	Render(input *input.StatusLineInput) string
 }
+
+// ContextComponent is implemented by components that draw on the shared
+// per-render Context (git snapshot, cost scans, settings). The registry calls
+// RenderWithContext instead of Render for these, so components that only
+// implement Component keep working unchanged.
+type ContextComponent interface {
+	Component
+	RenderWithContext(input *input.StatusLineInput, ctx *Context) string
+}
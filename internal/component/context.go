@@ -0,0 +1,241 @@
+package component
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/h2ik/claude-statusline/internal/claude"
+	"github.com/h2ik/claude-statusline/internal/cost"
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/vcs"
+)
+
+// Context carries per-render state shared by every component. Expensive
+// lookups -- the git snapshot, transcript cost scans, Claude settings -- are
+// computed on first use and memoized for the rest of the render, so several
+// components needing the same data trigger only one subprocess or scan.
+// All methods are safe for concurrent use by components rendering in parallel.
+type Context struct {
+	Input *input.StatusLineInput
+
+	settingsPath string
+	settingsOnce sync.Once
+	settings     *claude.Settings
+
+	gitOpts  git.Options
+	gitCache *git.StateCache
+	gitOnce  sync.Once
+	git      *vcs.GitRepo
+
+	mu    sync.Mutex
+	memos map[string]*memo
+}
+
+// memo holds one lazily computed value.
+type memo struct {
+	once  sync.Once
+	value any
+}
+
+// NewContext creates a Context for one render of in. settingsPath points at
+// Claude Code's settings.json; an empty path makes Settings return nil.
+func NewContext(in *input.StatusLineInput, settingsPath string) *Context {
+	return &Context{
+		Input:        in,
+		settingsPath: settingsPath,
+		memos:        make(map[string]*memo),
+	}
+}
+
+// SetGitOptions configures large-repo detection for the git snapshot. It
+// must be called before the first call to Git or VCS.
+func (c *Context) SetGitOptions(opts git.Options) {
+	c.gitOpts = opts
+}
+
+// SetGitCache makes the git accessors below reuse results from earlier
+// renders while the repository is unchanged. It must be called before any of
+// them.
+func (c *Context) SetGitCache(sc *git.StateCache) {
+	c.gitCache = sc
+}
+
+// Git returns the repository snapshot for the workspace's current directory.
+// It is taken regardless of which VCS Detect picks, so git-only components
+// keep working in colocated Jujutsu repos.
+func (c *Context) Git() *git.Snapshot {
+	return c.gitRepo().Snapshot()
+}
+
+// gitRepo returns the git backend for the workspace's current directory.
+func (c *Context) gitRepo() *vcs.GitRepo {
+	c.gitOnce.Do(func() {
+		c.git = vcs.NewGit(c.Input.Workspace.CurrentDir, c.gitOpts, c.gitCache)
+	})
+	return c.git
+}
+
+// VCS returns the backend for the version control system managing the
+// workspace's current directory, or nil outside any repository.
+func (c *Context) VCS() vcs.Repo {
+	repo, _ := c.memoize("vcs", func() any {
+		dir := c.Input.Workspace.CurrentDir
+		kind, root := vcs.Detect(dir)
+		switch kind {
+		case vcs.Git:
+			return c.gitRepo()
+		case vcs.Jujutsu:
+			return vcs.NewJujutsu(dir)
+		case vcs.Mercurial:
+			return vcs.NewMercurial(dir, root)
+		}
+		return nil
+	}).(vcs.Repo)
+	return repo
+}
+
+// Commits returns the number of commits matching q, or 0 outside a
+// repository.
+func (c *Context) Commits(q vcs.CommitQuery) int {
+	key := fmt.Sprintf("vcs:commits:%d:%q:%t", q.Since.Unix(), q.Authors, q.AllBranches)
+	return c.memoize(key, func() any {
+		repo := c.VCS()
+		if repo == nil {
+			return 0
+		}
+		count, _ := repo.CountCommits(q)
+		return count
+	}).(int)
+}
+
+// LastCommit returns the HEAD commit and its nearest tag, or nil outside a
+// git repository or before the first commit.
+func (c *Context) LastCommit() *git.Commit {
+	return c.memoize("git:last-commit", func() any {
+		if !c.Git().IsRepo {
+			return (*git.Commit)(nil)
+		}
+		dir := c.Input.Workspace.CurrentDir
+		var commit *git.Commit
+		if c.gitCache != nil {
+			commit, _ = c.gitCache.LastCommit(dir)
+		} else {
+			commit, _ = git.GetLastCommit(dir)
+		}
+		return commit
+	}).(*git.Commit)
+}
+
+// DiffStat returns the uncommitted changes to tracked files relative to HEAD,
+// or nil outside a git repository or when git diff fails.
+func (c *Context) DiffStat() *git.DiffStat {
+	return c.memoize("git:diff-stat", func() any {
+		if !c.Git().IsRepo {
+			return (*git.DiffStat)(nil)
+		}
+		dir := c.Input.Workspace.CurrentDir
+		var d *git.DiffStat
+		if c.gitCache != nil {
+			d, _ = c.gitCache.DiffStat(dir)
+		} else {
+			d, _ = git.GetDiffStat(dir)
+		}
+		return d
+	}).(*git.DiffStat)
+}
+
+// SubmoduleStatus returns the state of the repository's submodules or
+// subrepos; it is zero outside a repository or when the lookup fails.
+func (c *Context) SubmoduleStatus() *vcs.SubmoduleStatus {
+	return c.memoize("vcs:submodule-status", func() any {
+		var st *vcs.SubmoduleStatus
+		if repo := c.VCS(); repo != nil {
+			st, _ = repo.SubmoduleStatus()
+		}
+		if st == nil {
+			st = &vcs.SubmoduleStatus{}
+		}
+		return st
+	}).(*vcs.SubmoduleStatus)
+}
+
+// Worktrees returns every worktree of the repository with its dirty state,
+// or nil outside a git repository or when git worktree list fails.
+func (c *Context) Worktrees() []git.Worktree {
+	return c.memoize("git:worktrees", func() any {
+		if !c.Git().IsRepo {
+			return []git.Worktree(nil)
+		}
+		dir := c.Input.Workspace.CurrentDir
+		var wts []git.Worktree
+		if c.gitCache != nil {
+			wts, _ = c.gitCache.Worktrees(dir)
+		} else {
+			wts, _ = git.ListWorktrees(dir)
+		}
+		return wts
+	}).([]git.Worktree)
+}
+
+// Settings returns Claude Code's parsed settings.json, or nil when no path
+// was configured or the file could not be read.
+func (c *Context) Settings() *claude.Settings {
+	c.settingsOnce.Do(func() {
+		if c.settingsPath == "" {
+			return
+		}
+		if s, err := claude.LoadSettings(c.settingsPath); err == nil {
+			c.settings = s
+		}
+	})
+	return c.settings
+}
+
+// WindowCost returns s.CalculateWindow(w), computed at most once per render.
+func (c *Context) WindowCost(s *cost.TranscriptScanner, w cost.Window) float64 {
+	key := fmt.Sprintf("cost:%p:window:%s", s, w)
+	return c.memoize(key, func() any { return s.CalculateWindow(w) }).(float64)
+}
+
+// ProjectCost returns s.CalculateProject(dir), computed at most once per
+// render.
+func (c *Context) ProjectCost(s *cost.TranscriptScanner, dir string) cost.ProjectCost {
+	key := fmt.Sprintf("cost:%p:project:%s", s, dir)
+	return c.memoize(key, func() any { return s.CalculateProject(dir) }).(cost.ProjectCost)
+}
+
+// ProjectWindowCost returns s.CalculateProjectWindow(dir, w), computed at
+// most once per render.
+func (c *Context) ProjectWindowCost(s *cost.TranscriptScanner, dir string, w cost.Window) float64 {
+	key := fmt.Sprintf("cost:%p:project-window:%s:%s", s, w, dir)
+	return c.memoize(key, func() any { return s.CalculateProjectWindow(dir, w) }).(float64)
+}
+
+// WindowModelUsage returns s.CalculateWindowByModel(w), computed at most
+// once per render.
+func (c *Context) WindowModelUsage(s *cost.TranscriptScanner, w cost.Window) map[string]cost.Usage {
+	key := fmt.Sprintf("cost:%p:models:%s", s, w)
+	return c.memoize(key, func() any { return s.CalculateWindowByModel(w) }).(map[string]cost.Usage)
+}
+
+// Forecast returns s.CalculateForecast(), computed at most once per render.
+func (c *Context) Forecast(s *cost.TranscriptScanner) cost.Forecast {
+	key := fmt.Sprintf("cost:%p:forecast", s)
+	return c.memoize(key, func() any { return s.CalculateForecast() }).(cost.Forecast)
+}
+
+// memoize returns the value stored under key, computing it with fn on first
+// use. Concurrent callers for the same key block until the first finishes.
+func (c *Context) memoize(key string, fn func() any) any {
+	c.mu.Lock()
+	m, ok := c.memos[key]
+	if !ok {
+		m = &memo{}
+		c.memos[key] = m
+	}
+	c.mu.Unlock()
+
+	m.once.Do(func() { m.value = fn() })
+	return m.value
+}
@@ -0,0 +1,117 @@
+package component
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/vcs"
+)
+
+type contextProbe struct {
+	name string
+	seen chan *Context
+}
+
+func (p *contextProbe) Name() string                            { return p.name }
+func (p *contextProbe) Render(in *input.StatusLineInput) string { return "plain" }
+func (p *contextProbe) RenderWithContext(in *input.StatusLineInput, ctx *Context) string {
+	p.seen <- ctx
+	return "with-context"
+}
+
+func TestContext_MemoizeComputesOnce(t *testing.T) {
+	ctx := NewContext(&input.StatusLineInput{}, "")
+	var calls int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := ctx.memoize("answer", func() any {
+				atomic.AddInt32(&calls, 1)
+				return 42
+			}).(int)
+			if v != 42 {
+				t.Errorf("expected 42, got %d", v)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected one computation, got %d", calls)
+	}
+}
+
+func TestContext_GitOutsideRepo(t *testing.T) {
+	dir := t.TempDir()
+	ctx := NewContext(&input.StatusLineInput{Workspace: input.Workspace{CurrentDir: dir}}, "")
+
+	snap := ctx.Git()
+	if snap.IsRepo {
+		t.Error("expected IsRepo=false for a plain directory")
+	}
+	if ctx.Git() != snap {
+		t.Error("expected Git() to return the memoized snapshot")
+	}
+	if ctx.Commits(vcs.CommitQuery{}) != 0 || ctx.SubmoduleStatus().Total != 0 {
+		t.Error("expected zero commits and submodules outside a repo")
+	}
+}
+
+func TestContext_VCS(t *testing.T) {
+	dir := t.TempDir()
+	ctx := NewContext(&input.StatusLineInput{Workspace: input.Workspace{CurrentDir: dir}}, "")
+	if ctx.VCS() != nil {
+		t.Error("expected no VCS for a plain directory")
+	}
+
+	_ = os.Mkdir(filepath.Join(dir, ".jj"), 0755)
+	ctx = NewContext(&input.StatusLineInput{Workspace: input.Workspace{CurrentDir: dir}}, "")
+	repo := ctx.VCS()
+	if repo == nil || repo.Kind() != vcs.Jujutsu {
+		t.Fatalf("expected a Jujutsu backend, got %v", repo)
+	}
+	if ctx.VCS() != repo {
+		t.Error("expected VCS() to return the memoized backend")
+	}
+}
+
+func TestContext_Settings(t *testing.T) {
+	if s := NewContext(&input.StatusLineInput{}, "").Settings(); s != nil {
+		t.Errorf("expected nil settings without a path, got %+v", s)
+	}
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	_ = os.WriteFile(path, []byte(`{"env":{"AWS_REGION":"us-west-2"}}`), 0644)
+
+	s := NewContext(&input.StatusLineInput{}, path).Settings()
+	if s == nil || s.AWSRegion != "us-west-2" {
+		t.Errorf("expected AWSRegion us-west-2, got %+v", s)
+	}
+}
+
+func TestRegistry_RenderAllWithContext_SharesContext(t *testing.T) {
+	seen := make(chan *Context, 2)
+	r := NewRegistry()
+	r.Register(&contextProbe{name: "p1", seen: seen})
+	r.Register(&contextProbe{name: "p2", seen: seen})
+	r.Register(&mockComponent{name: "legacy", output: "legacy"})
+
+	ctx := NewContext(&input.StatusLineInput{}, "")
+	rendered := r.RenderAllWithContext(ctx, []string{"p1", "p2", "legacy"})
+
+	if rendered["p1"] != "with-context" || rendered["legacy"] != "legacy" {
+		t.Errorf("unexpected outputs: %v", rendered)
+	}
+	for i := 0; i < 2; i++ {
+		if got := <-seen; got != ctx {
+			t.Error("expected every ContextComponent to receive the shared context")
+		}
+	}
+}
@@ -3,6 +3,8 @@ package component
 import (
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/h2ik/claude-statusline/internal/input"
 )
@@ -10,11 +12,34 @@ import (
 // Registry holds named components and renders them in order.
 type Registry struct {
 	components map[string]Component
+
+	// timeout is the default per-component deadline and budget caps the whole
+	// render pass. Zero disables the corresponding limit.
+	timeout   time.Duration
+	budget    time.Duration
+	overrides map[string]time.Duration
+
+	// stale, when set, supplies the previous output for components that miss
+	// their deadline; dim marks that output as stale and refresh is asked to
+	// recompute the missed components out of band for the next render.
+	stale   StaleStore
+	dim     func(string) string
+	refresh func(names []string)
+}
+
+// StaleStore persists the last successful output of each component so a
+// render that times out can fall back to it.
+type StaleStore interface {
+	Load(name string) (string, bool)
+	Save(name, output string)
 }
 
 // NewRegistry creates a Registry ready to accept component registrations.
 func NewRegistry() *Registry {
-	return &Registry{components: make(map[string]Component)}
+	return &Registry{
+		components: make(map[string]Component),
+		overrides:  make(map[string]time.Duration),
+	}
 }
 
 // Register adds a component to the registry, keyed by its Name().
@@ -27,45 +52,174 @@ func (r *Registry) Get(name string) Component {
 	return r.components[name]
 }
 
-// RenderLine iterates over the requested component names in order, renders
-// each one, and returns only the non-empty results. Panics in individual
-// components are recovered so one broken component cannot crash the binary.
-func (r *Registry) RenderLine(in *input.StatusLineInput, names []string) []string {
-	var output []string
+// SetTimeouts configures the default per-component deadline and the overall
+// render budget. A component that has not returned by the earlier of the two
+// is dropped from the output (or replaced, see SetStale). Zero disables the
+// corresponding limit.
+func (r *Registry) SetTimeouts(perComponent, budget time.Duration) {
+	r.timeout = perComponent
+	r.budget = budget
+}
+
+// SetComponentTimeout overrides the default deadline for a single component.
+func (r *Registry) SetComponentTimeout(name string, d time.Duration) {
+	r.overrides[name] = d
+}
+
+// SetStale enables stale-while-revalidate: every completed render is saved to
+// store, and a component that misses its deadline is shown with its saved
+// output passed through dim. refresh (may be nil) receives the names of the
+// components that timed out once the render pass is over.
+func (r *Registry) SetStale(store StaleStore, dim func(string) string, refresh func(names []string)) {
+	r.stale = store
+	r.dim = dim
+	r.refresh = refresh
+}
+
+// Rendered maps component names to their output from a single RenderAll pass.
+type Rendered map[string]string
+
+// Line returns parallel slices of names and outputs for the requested
+// component names, in the requested order, skipping empty or missing results.
+func (rd Rendered) Line(names []string) (outNames, outContent []string) {
 	for _, name := range names {
-		if c := r.Get(name); c != nil {
-			if rendered := safeRender(c, in); rendered != "" {
-				output = append(output, rendered)
-			}
+		if rendered := rd[name]; rendered != "" {
+			outNames = append(outNames, name)
+			outContent = append(outContent, rendered)
 		}
 	}
-	return output
+	return outNames, outContent
 }
 
-// RenderNamedLine iterates over the requested component names in order, renders
-// each one, and returns parallel slices of names and rendered outputs for only the
-// non-empty results. This lets callers (e.g. powerline style) know which component
-// produced which output.
-func (r *Registry) RenderNamedLine(in *input.StatusLineInput, names []string) (outNames, outContent []string) {
+// RenderAll renders the named components with a fresh Context for in.
+// See RenderAllWithContext.
+func (r *Registry) RenderAll(in *input.StatusLineInput, names []string) Rendered {
+	return r.RenderAllWithContext(NewContext(in, ""), names)
+}
+
+// RenderAllWithContext renders every distinct registered component in names
+// concurrently, sharing ctx between them, and waits for each one until its
+// deadline (see SetTimeouts). Components that miss their deadline are replaced
+// by their last saved output when a StaleStore is configured, and left out of
+// the result otherwise; their goroutines are abandoned and die with the
+// process. Unknown names are ignored.
+func (r *Registry) RenderAllWithContext(ctx *Context, names []string) Rendered {
+	out := make(Rendered, len(names))
+	start := time.Now()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var timedOut []string
+	seen := make(map[string]bool, len(names))
 	for _, name := range names {
-		if c := r.Get(name); c != nil {
-			if rendered := safeRender(c, in); rendered != "" {
-				outNames = append(outNames, name)
-				outContent = append(outContent, rendered)
-			}
+		c := r.Get(name)
+		if c == nil || seen[name] {
+			continue
 		}
+		seen[name] = true
+
+		wg.Add(1)
+		go func(name string, c Component) {
+			defer wg.Done()
+
+			// Buffered so an abandoned render can still complete its send.
+			done := make(chan string, 1)
+			go func() { done <- safeRender(c, ctx) }()
+
+			var expired <-chan time.Time
+			limit := r.limitFor(name)
+			if limit > 0 {
+				timer := time.NewTimer(limit - time.Since(start))
+				defer timer.Stop()
+				expired = timer.C
+			}
+
+			select {
+			case rendered := <-done:
+				if r.stale != nil {
+					r.stale.Save(name, rendered)
+				}
+				mu.Lock()
+				out[name] = rendered
+				mu.Unlock()
+			case <-expired:
+				fmt.Fprintf(os.Stderr, "component %s timed out after %v\n", name, limit)
+				fallback := r.fallback(name)
+				mu.Lock()
+				timedOut = append(timedOut, name)
+				if fallback != "" {
+					out[name] = fallback
+				}
+				mu.Unlock()
+			}
+		}(name, c)
 	}
-	return outNames, outContent
+	wg.Wait()
+
+	if len(timedOut) > 0 && r.refresh != nil {
+		r.refresh(timedOut)
+	}
+
+	return out
 }
 
-// safeRender calls c.Render and recovers from panics, logging to stderr
-// and returning an empty string so other components continue rendering.
-func safeRender(c Component, in *input.StatusLineInput) (result string) {
+// fallback returns the dimmed last saved output for a component, or "" when
+// stale-while-revalidate is disabled or nothing has been saved yet.
+func (r *Registry) fallback(name string) string {
+	if r.stale == nil {
+		return ""
+	}
+	last, ok := r.stale.Load(name)
+	if !ok || last == "" {
+		return ""
+	}
+	if r.dim != nil {
+		return r.dim(last)
+	}
+	return last
+}
+
+// limitFor returns the effective deadline for a component, measured from the
+// start of the render pass: the smaller of its own timeout and the budget.
+func (r *Registry) limitFor(name string) time.Duration {
+	limit := r.timeout
+	if d, ok := r.overrides[name]; ok {
+		limit = d
+	}
+	if r.budget > 0 && (limit <= 0 || r.budget < limit) {
+		limit = r.budget
+	}
+	return limit
+}
+
+// RenderLine renders the requested components concurrently and returns only
+// the non-empty results, in the requested order. Panics in individual
+// components are recovered so one broken component cannot crash the binary.
+func (r *Registry) RenderLine(in *input.StatusLineInput, names []string) []string {
+	_, output := r.RenderAll(in, names).Line(names)
+	return output
+}
+
+// RenderNamedLine renders the requested components concurrently and returns
+// parallel slices of names and rendered outputs for only the non-empty
+// results, in the requested order. This lets callers (e.g. powerline style)
+// know which component produced which output.
+func (r *Registry) RenderNamedLine(in *input.StatusLineInput, names []string) (outNames, outContent []string) {
+	return r.RenderAll(in, names).Line(names)
+}
+
+// safeRender renders c -- through RenderWithContext when it is a
+// ContextComponent -- and recovers from panics, logging to stderr and
+// returning an empty string so other components continue rendering.
+func safeRender(c Component, ctx *Context) (result string) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Fprintf(os.Stderr, "component %s panicked: %v\n", c.Name(), r)
 			result = ""
 		}
 	}()
-	return c.Render(in)
+	if cc, ok := c.(ContextComponent); ok {
+		return cc.RenderWithContext(ctx.Input, ctx)
+	}
+	return c.Render(ctx.Input)
 }
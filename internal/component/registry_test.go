@@ -1,7 +1,9 @@
 package component
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/h2ik/claude-statusline/internal/input"
 )
@@ -14,6 +16,22 @@ type mockComponent struct {
 func (m *mockComponent) Name() string                            { return m.name }
 func (m *mockComponent) Render(in *input.StatusLineInput) string { return m.output }
 
+type slowComponent struct {
+	name  string
+	delay time.Duration
+}
+
+func (s *slowComponent) Name() string { return s.name }
+func (s *slowComponent) Render(in *input.StatusLineInput) string {
+	time.Sleep(s.delay)
+	return s.name
+}
+
+type panicComponent struct{}
+
+func (p *panicComponent) Name() string                            { return "boom" }
+func (p *panicComponent) Render(in *input.StatusLineInput) string { panic("kaboom") }
+
 func TestRegistry_Register(t *testing.T) {
 	r := NewRegistry()
 	comp := &mockComponent{name: "test", output: "hello"}
@@ -103,3 +121,150 @@ func TestRegistry_RenderNamedLine_Empty(t *testing.T) {
 		t.Errorf("expected empty slices for nonexistent components")
 	}
 }
+
+func TestRegistry_RenderAll_RunsConcurrently(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&slowComponent{name: "a", delay: 100 * time.Millisecond})
+	r.Register(&slowComponent{name: "b", delay: 100 * time.Millisecond})
+	r.Register(&slowComponent{name: "c", delay: 100 * time.Millisecond})
+
+	start := time.Now()
+	rendered := r.RenderAll(&input.StatusLineInput{}, []string{"a", "b", "c"})
+	elapsed := time.Since(start)
+
+	if len(rendered) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(rendered))
+	}
+	if elapsed >= 250*time.Millisecond {
+		t.Errorf("expected concurrent render well under 300ms, took %v", elapsed)
+	}
+}
+
+func TestRegistry_RenderAll_DropsTimedOutComponent(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockComponent{name: "fast", output: "fast"})
+	r.Register(&slowComponent{name: "slow", delay: time.Second})
+	r.SetTimeouts(50*time.Millisecond, 0)
+
+	names, outputs := r.RenderAll(&input.StatusLineInput{}, []string{"slow", "fast"}).Line([]string{"slow", "fast"})
+	if len(names) != 1 || names[0] != "fast" || outputs[0] != "fast" {
+		t.Errorf("expected only [fast], got %v / %v", names, outputs)
+	}
+}
+
+func TestRegistry_RenderAll_ComponentTimeoutOverride(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&slowComponent{name: "slow", delay: 80 * time.Millisecond})
+	r.SetTimeouts(20*time.Millisecond, 0)
+	r.SetComponentTimeout("slow", 500*time.Millisecond)
+
+	rendered := r.RenderAll(&input.StatusLineInput{}, []string{"slow"})
+	if rendered["slow"] != "slow" {
+		t.Errorf("expected override to let slow component finish, got %q", rendered["slow"])
+	}
+}
+
+func TestRegistry_RenderAll_BudgetCapsComponentTimeout(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&slowComponent{name: "slow", delay: 200 * time.Millisecond})
+	r.SetTimeouts(time.Second, 50*time.Millisecond)
+
+	start := time.Now()
+	rendered := r.RenderAll(&input.StatusLineInput{}, []string{"slow"})
+	if _, ok := rendered["slow"]; ok {
+		t.Error("expected slow component to be dropped by the render budget")
+	}
+	if elapsed := time.Since(start); elapsed >= 150*time.Millisecond {
+		t.Errorf("expected budget to cut render short, took %v", elapsed)
+	}
+}
+
+func TestRegistry_RenderAll_RecoversPanics(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&panicComponent{})
+	r.Register(&mockComponent{name: "ok", output: "fine"})
+
+	names, _ := r.RenderAll(&input.StatusLineInput{}, []string{"boom", "ok"}).Line([]string{"boom", "ok"})
+	if len(names) != 1 || names[0] != "ok" {
+		t.Errorf("expected panicking component to be skipped, got %v", names)
+	}
+}
+
+func TestRendered_Line_PreservesRequestedOrder(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&slowComponent{name: "first", delay: 60 * time.Millisecond})
+	r.Register(&slowComponent{name: "second", delay: 0})
+
+	rendered := r.RenderAll(&input.StatusLineInput{}, []string{"first", "second"})
+	names, _ := rendered.Line([]string{"first", "second"})
+	if len(names) != 2 || names[0] != "first" || names[1] != "second" {
+		t.Errorf("expected [first second], got %v", names)
+	}
+}
+
+type memStore struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (m *memStore) Load(name string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[name]
+	return v, ok
+}
+
+func (m *memStore) Save(name, output string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[name] = output
+}
+
+func TestRegistry_RenderAll_SavesCompletedOutput(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockComponent{name: "c1", output: "first"})
+	store := &memStore{data: map[string]string{}}
+	r.SetStale(store, nil, nil)
+
+	r.RenderAll(&input.StatusLineInput{}, []string{"c1"})
+	if got, _ := store.Load("c1"); got != "first" {
+		t.Errorf("expected completed output to be saved, got %q", got)
+	}
+}
+
+func TestRegistry_RenderAll_ServesStaleOnTimeout(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&slowComponent{name: "slow", delay: time.Second})
+	r.Register(&mockComponent{name: "fast", output: "fast"})
+	r.SetTimeouts(30*time.Millisecond, 0)
+
+	store := &memStore{data: map[string]string{"slow": "previous"}}
+	var refreshed []string
+	r.SetStale(store,
+		func(s string) string { return "dim(" + s + ")" },
+		func(names []string) { refreshed = names },
+	)
+
+	rendered := r.RenderAll(&input.StatusLineInput{}, []string{"slow", "fast"})
+	if rendered["slow"] != "dim(previous)" {
+		t.Errorf("expected dimmed stale output, got %q", rendered["slow"])
+	}
+	if rendered["fast"] != "fast" {
+		t.Errorf("expected fast output, got %q", rendered["fast"])
+	}
+	if len(refreshed) != 1 || refreshed[0] != "slow" {
+		t.Errorf("expected refresh for [slow], got %v", refreshed)
+	}
+}
+
+func TestRegistry_RenderAll_NoRefreshWhenAllComplete(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&mockComponent{name: "c1", output: "first"})
+	called := false
+	r.SetStale(&memStore{data: map[string]string{}}, nil, func([]string) { called = true })
+
+	r.RenderAll(&input.StatusLineInput{}, []string{"c1"})
+	if called {
+		t.Error("expected no refresh when nothing timed out")
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/h2ik/claude-statusline/internal/cache"
 	"github.com/h2ik/claude-statusline/internal/claude"
+	"github.com/h2ik/claude-statusline/internal/component"
 	"github.com/h2ik/claude-statusline/internal/config"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
@@ -28,6 +29,7 @@ type BedrockModel struct {
 }
 
 // NewBedrockModel creates a new BedrockModel component with the given renderer, cache, config, and optional Claude settings.
+// When s is nil, settings are taken from the per-render Context instead.
 func NewBedrockModel(r *render.Renderer, c *cache.Cache, cfg *config.Config, s *claude.Settings, ic icons.IconSet) *BedrockModel {
 	return &BedrockModel{renderer: r, cache: c, config: cfg, settings: s, icons: ic}
 }
@@ -61,6 +63,17 @@ func (c *BedrockModel) Render(in *input.StatusLineInput) string {
 	return fmt.Sprintf("%s %s", icon, c.renderer.Teal(name))
 }
 
+// RenderWithContext renders like Render, taking Claude settings from ctx when
+// none were supplied at construction.
+func (c *BedrockModel) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	if c.settings != nil {
+		return c.Render(in)
+	}
+	withSettings := *c
+	withSettings.settings = ctx.Settings()
+	return withSettings.Render(in)
+}
+
 // resolveBedrockARN resolves a Bedrock inference profile ARN to a friendly name
 // and region, using the cache to avoid repeated AWS CLI calls.
 // The cache stores "name\tregion" so both values survive round-trips.
@@ -0,0 +1,113 @@
+package components
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/cost"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// budgetWindows are the calendar windows the [budget] limits cover, in the
+// order the budget component shows them.
+var budgetWindows = []struct {
+	window cost.Window
+	limit  func(config.BudgetLimits) float64
+}{
+	{cost.Window{Calendar: cost.Day}, func(l config.BudgetLimits) float64 { return l.Daily }},
+	{cost.Window{Calendar: cost.Week}, func(l config.BudgetLimits) float64 { return l.Weekly }},
+	{cost.Window{Calendar: cost.Month}, func(l config.BudgetLimits) float64 { return l.Monthly }},
+}
+
+// Budget renders transcript spending against the [budget] limits: one
+// "spent/limit percent" entry per configured window, then the current
+// project's own limits, each colored by how much of the limit is used, e.g.
+// "💰 TODAY $12.40/$20 62% · MONTH $310.00/$400 78% · app TODAY $6.10/$5 122%".
+// Returns an empty string when no limit applies.
+type Budget struct {
+	renderer *render.Renderer
+	scanner  *cost.TranscriptScanner
+	config   *config.Config
+	icons    icons.IconSet
+}
+
+// NewBudget creates the budget component.
+func NewBudget(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet) *Budget {
+	return &Budget{renderer: r, scanner: s, config: cfg, icons: ic}
+}
+
+func (c *Budget) Name() string {
+	return "budget"
+}
+
+func (c *Budget) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext reads spending through ctx, sharing the scans of the
+// cost components in the same render.
+func (c *Budget) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	var parts []string
+
+	global := c.config.BudgetLimits()
+	for _, b := range budgetWindows {
+		if limit := b.limit(global); limit > 0 {
+			parts = append(parts, c.entry(b.window.Label(), ctx.WindowCost(c.scanner, b.window), limit))
+		}
+	}
+
+	dir := in.Workspace.ProjectDir
+	if dir == "" {
+		dir = in.Workspace.CurrentDir
+	}
+	if project := c.config.ProjectBudget(dir); project != (config.BudgetLimits{}) {
+		name := filepath.Base(dir)
+		for _, b := range budgetWindows {
+			if limit := b.limit(project); limit > 0 {
+				spent := ctx.ProjectWindowCost(c.scanner, dir, b.window)
+				parts = append(parts, c.entry(name+" "+b.window.Label(), spent, limit))
+			}
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return c.icons.Get(icons.Money) + " " + strings.Join(parts, " · ")
+}
+
+// entry formats one window's spending against its limit.
+func (c *Budget) entry(label string, spent, limit float64) string {
+	color := budgetColor(c.renderer, spent, limit, c.config.BudgetWarnAt())
+	return fmt.Sprintf("%s %s",
+		c.renderer.Dimmed(label),
+		color(fmt.Sprintf("$%.2f/%s %.0f%%", spent, budgetAmount(limit), spent/limit*100)),
+	)
+}
+
+// budgetColor returns the color for spent against limit: green below warnAt
+// of the limit, yellow from there up to the limit, and red once it is
+// reached.
+func budgetColor(r *render.Renderer, spent, limit, warnAt float64) func(string) string {
+	switch ratio := spent / limit; {
+	case ratio >= 1:
+		return r.Red
+	case ratio >= warnAt:
+		return r.Yellow
+	default:
+		return r.Green
+	}
+}
+
+// budgetAmount formats a limit in whole dollars when it has no cents.
+func budgetAmount(usd float64) string {
+	if usd == float64(int64(usd)) {
+		return fmt.Sprintf("$%d", int64(usd))
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
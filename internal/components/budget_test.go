@@ -0,0 +1,157 @@
+package components
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cache"
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/cost"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// budgetScanner returns a scanner over one /Users/test/app transcript that
+// cost $17.50 a minute ago.
+func budgetScanner(t *testing.T) *cost.TranscriptScanner {
+	t.Helper()
+	projectsDir := t.TempDir()
+	projDir := filepath.Join(projectsDir, "-Users-test-app")
+	_ = os.MkdirAll(projDir, 0755)
+	// Opus: (1000000*5 + 500000*25)/1M = 17.50
+	_ = os.WriteFile(filepath.Join(projDir, "session.jsonl"), []byte(
+		`{"type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000000,"output_tokens":500000,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"`+time.Now().Add(-1*time.Minute).Format(time.RFC3339Nano)+`"}`+"\n",
+	), 0644)
+	return cost.NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+}
+
+func appInput() *input.StatusLineInput {
+	in := &input.StatusLineInput{}
+	in.Workspace.CurrentDir = "/Users/test/app"
+	in.Workspace.ProjectDir = "/Users/test/app"
+	return in
+}
+
+func TestBudgetColor(t *testing.T) {
+	r := render.New(nil)
+	tests := []struct {
+		spent float64
+		want  func(string) string
+	}{
+		{5, r.Green},
+		{8, r.Yellow},
+		{10, r.Red},
+		{12, r.Red},
+	}
+	for _, tt := range tests {
+		if got := budgetColor(r, tt.spent, 10, 0.8)("x"); got != tt.want("x") {
+			t.Errorf("budgetColor(%v of 10) = %q, want %q", tt.spent, got, tt.want("x"))
+		}
+	}
+}
+
+func TestBudget_Render(t *testing.T) {
+	r := render.New(nil)
+	cfg := &config.Config{Budget: &config.BudgetConfig{
+		BudgetLimits: config.BudgetLimits{Daily: 20, Monthly: 400},
+		Projects: map[string]config.BudgetLimits{
+			"/Users/test/app": {Daily: 10},
+		},
+	}}
+	c := NewBudget(r, budgetScanner(t), cfg, icons.New("emoji"))
+
+	output := c.Render(appInput())
+	for _, want := range []string{
+		r.Yellow("$17.50/$20 88%"),
+		r.Green("$17.50/$400 4%"),
+		r.Red("$17.50/$10 175%"),
+		"app TODAY",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "WEEK") {
+		t.Errorf("expected no weekly entry without a weekly limit, got: %s", output)
+	}
+}
+
+func TestBudget_Render_CalendarWindows(t *testing.T) {
+	projectsDir := t.TempDir()
+	projDir := filepath.Join(projectsDir, "-Users-test-app")
+	_ = os.MkdirAll(projDir, 0755)
+	// $17.50 a minute ago, and $17.50 an hour before the calendar week and
+	// month began: inside the rolling 7 and 30 days, outside the calendar
+	// windows the limits cover.
+	line := func(ts time.Time) string {
+		return `{"type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000000,"output_tokens":500000,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"cwd":"/Users/test/app","timestamp":"` + ts.Format(time.RFC3339Nano) + `"}` + "\n"
+	}
+	now := time.Now()
+	before := cost.Week.Start(now)
+	if m := cost.Month.Start(now); m.Before(before) {
+		before = m
+	}
+	_ = os.WriteFile(filepath.Join(projDir, "session.jsonl"), []byte(line(before.Add(-time.Hour))+line(now.Add(-time.Minute))), 0644)
+	s := cost.NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+
+	r := render.New(nil)
+	cfg := &config.Config{Budget: &config.BudgetConfig{
+		BudgetLimits: config.BudgetLimits{Weekly: 100, Monthly: 400},
+		Projects: map[string]config.BudgetLimits{
+			"/Users/test/app": {Monthly: 100},
+		},
+	}}
+	output := NewBudget(r, s, cfg, icons.New("emoji")).Render(appInput())
+	for _, want := range []string{
+		"WEEK", r.Green("$17.50/$100 18%"),
+		"MONTH", r.Green("$17.50/$400 4%"),
+		"app MONTH",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Contains(output, "$35.00") {
+		t.Errorf("expected spend before the calendar windows to be left out, got: %s", output)
+	}
+}
+
+func TestBudget_Render_NoLimits(t *testing.T) {
+	c := NewBudget(render.New(nil), budgetScanner(t), &config.Config{}, icons.New("emoji"))
+	if output := c.Render(appInput()); output != "" {
+		t.Errorf("expected empty output without limits, got: %s", output)
+	}
+}
+
+func TestCostDaily_Render_BudgetColor(t *testing.T) {
+	r := render.New(nil)
+	on := true
+	cfg := &config.Config{
+		Components: map[string]config.ComponentConfig{"cost_daily": {BudgetColor: &on}},
+		Budget:     &config.BudgetConfig{BudgetLimits: config.BudgetLimits{Daily: 10, Monthly: 400}},
+	}
+	s := budgetScanner(t)
+
+	if output := NewCostDaily(r, s, cfg, icons.New("emoji")).Render(appInput()); !strings.Contains(output, r.Red("$17.50")) {
+		t.Errorf("expected today's total in red over the daily budget, got: %s", output)
+	}
+	// cost_monthly has a limit but not budget_color.
+	if output := NewCostMonthly(r, s, cfg, icons.New("emoji")).Render(appInput()); !strings.HasSuffix(output, " $17.50") {
+		t.Errorf("expected a plain total without budget_color, got: %q", output)
+	}
+
+	// A rolling 30 days is not the calendar month the monthly limit covers.
+	cfg.Components["cost_monthly"] = config.ComponentConfig{BudgetColor: &on}
+	if output := NewCostMonthly(r, s, cfg, icons.New("emoji")).Render(appInput()); !strings.HasSuffix(output, " $17.50") {
+		t.Errorf("expected a plain total for the rolling 30 days, got: %q", output)
+	}
+	month := "month"
+	cfg.Components["cost_monthly"] = config.ComponentConfig{BudgetColor: &on, Window: &month}
+	if output := NewCostMonthly(r, s, cfg, icons.New("emoji")).Render(appInput()); !strings.Contains(output, r.Green("$17.50")) {
+		t.Errorf("expected the calendar month colored against the monthly limit, got: %q", output)
+	}
+}
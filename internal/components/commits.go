@@ -2,24 +2,31 @@ package components
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/config"
 	"github.com/h2ik/claude-statusline/internal/git"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
 	"github.com/h2ik/claude-statusline/internal/render"
+	"github.com/h2ik/claude-statusline/internal/vcs"
 )
 
-// Commits renders the number of git commits made today on the current branch.
-// Returns an empty string if the directory is not a git repo or there are no
-// commits today.
+// Commits renders the number of commits made in a window (today by default)
+// on the current branch, optionally across all local branches (or jj
+// bookmarks) and restricted to certain authors or co-authors.
+// Returns an empty string outside a repository or when there are no matching
+// commits.
 type Commits struct {
 	renderer *render.Renderer
+	config   *config.Config
 	icons    icons.IconSet
 }
 
-// NewCommits creates a new Commits component with the given renderer.
-func NewCommits(r *render.Renderer, ic icons.IconSet) *Commits {
-	return &Commits{renderer: r, icons: ic}
+// NewCommits creates a new Commits component with the given renderer and config.
+func NewCommits(r *render.Renderer, cfg *config.Config, ic icons.IconSet) *Commits {
+	return &Commits{renderer: r, config: cfg, icons: ic}
 }
 
 // Name returns the component identifier used for registry lookup.
@@ -27,16 +34,42 @@ func (c *Commits) Name() string {
 	return "commits"
 }
 
-// Render produces the commits-today string from the given input.
+// Render produces the commits string from the given input.
 func (c *Commits) Render(in *input.StatusLineInput) string {
-	count, err := git.GetCommitsToday(in.Workspace.CurrentDir)
-	if err != nil || count == 0 {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext produces the commits string using the shared per-render
+// repository state from ctx.
+func (c *Commits) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	since, label := commitWindow(c.config.GetString("commits", "window", "today"), time.Now())
+	count := ctx.Commits(vcs.CommitQuery{
+		Since:       since,
+		Authors:     c.config.GetStrings("commits", "authors", nil),
+		AllBranches: c.config.GetBool("commits", "all_branches", false),
+	})
+	if count == 0 {
 		return ""
 	}
 
 	return fmt.Sprintf("%s %s %d",
 		c.icons.Get(icons.FloppyDisk),
-		c.renderer.Dimmed("Commits:"),
+		c.renderer.Dimmed(label),
 		count,
 	)
 }
+
+// commitWindow returns the start of the named counting window at now and the
+// label to render: "today" (since midnight), "24h" (rolling, to the minute
+// so results can be cached), or "week" (since Monday). Unknown names fall
+// back to today.
+func commitWindow(window string, now time.Time) (time.Time, string) {
+	switch window {
+	case "24h":
+		return now.Add(-24 * time.Hour).Truncate(time.Minute), "Commits (24h):"
+	case "week":
+		return git.StartOfWeek(now), "Commits (week):"
+	default:
+		return git.StartOfDay(now), "Commits:"
+	}
+}
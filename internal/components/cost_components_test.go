@@ -1,13 +1,16 @@
 package components
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/h2ik/claude-statusline/internal/cache"
+	"github.com/h2ik/claude-statusline/internal/config"
 	"github.com/h2ik/claude-statusline/internal/cost"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
@@ -22,7 +25,7 @@ func TestCostMonthly_Name(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(t.TempDir(), ca)
-	c := NewCostMonthly(r, s, icons.New("emoji"))
+	c := NewCostMonthly(r, s, &config.Config{}, icons.New("emoji"))
 
 	if c.Name() != "cost_monthly" {
 		t.Errorf("expected 'cost_monthly', got %q", c.Name())
@@ -33,7 +36,7 @@ func TestCostMonthly_Render_EmptyHistory(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(t.TempDir(), ca)
-	c := NewCostMonthly(r, s, icons.New("emoji"))
+	c := NewCostMonthly(r, s, &config.Config{}, icons.New("emoji"))
 
 	in := &input.StatusLineInput{}
 
@@ -57,7 +60,7 @@ func TestCostMonthly_Render_WithEntries(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(projectsDir, ca)
-	c := NewCostMonthly(r, s, icons.New("emoji"))
+	c := NewCostMonthly(r, s, &config.Config{}, icons.New("emoji"))
 	in := &input.StatusLineInput{}
 
 	output := c.Render(in)
@@ -74,7 +77,7 @@ func TestCostWeekly_Name(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(t.TempDir(), ca)
-	c := NewCostWeekly(r, s, icons.New("emoji"))
+	c := NewCostWeekly(r, s, &config.Config{}, icons.New("emoji"))
 
 	if c.Name() != "cost_weekly" {
 		t.Errorf("expected 'cost_weekly', got %q", c.Name())
@@ -85,7 +88,7 @@ func TestCostWeekly_Render_EmptyHistory(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(t.TempDir(), ca)
-	c := NewCostWeekly(r, s, icons.New("emoji"))
+	c := NewCostWeekly(r, s, &config.Config{}, icons.New("emoji"))
 
 	in := &input.StatusLineInput{}
 
@@ -112,7 +115,7 @@ func TestCostWeekly_Render_FiltersOldEntries(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(projectsDir, ca)
-	c := NewCostWeekly(r, s, icons.New("emoji"))
+	c := NewCostWeekly(r, s, &config.Config{}, icons.New("emoji"))
 	in := &input.StatusLineInput{}
 
 	output := c.Render(in)
@@ -122,14 +125,14 @@ func TestCostWeekly_Render_FiltersOldEntries(t *testing.T) {
 }
 
 // ============================================================
-// CostDaily (CostToday) tests
+// CostDaily tests
 // ============================================================
 
 func TestCostDaily_Name(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(t.TempDir(), ca)
-	c := NewCostDaily(r, s, icons.New("emoji"))
+	c := NewCostDaily(r, s, &config.Config{}, icons.New("emoji"))
 
 	if c.Name() != "cost_daily" {
 		t.Errorf("expected 'cost_daily', got %q", c.Name())
@@ -140,7 +143,7 @@ func TestCostDaily_Render_EmptyHistory(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(t.TempDir(), ca)
-	c := NewCostDaily(r, s, icons.New("emoji"))
+	c := NewCostDaily(r, s, &config.Config{}, icons.New("emoji"))
 
 	in := &input.StatusLineInput{}
 
@@ -170,7 +173,7 @@ func TestCostDaily_Render_IncludesTodayEntries(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(projectsDir, ca)
-	c := NewCostDaily(r, s, icons.New("emoji"))
+	c := NewCostDaily(r, s, &config.Config{}, icons.New("emoji"))
 	in := &input.StatusLineInput{}
 
 	output := c.Render(in)
@@ -195,7 +198,7 @@ func TestCostDaily_Render_ExcludesYesterdayEntries(t *testing.T) {
 	r := render.New(nil)
 	ca := cache.New(t.TempDir())
 	s := cost.NewTranscriptScanner(projectsDir, ca)
-	c := NewCostDaily(r, s, icons.New("emoji"))
+	c := NewCostDaily(r, s, &config.Config{}, icons.New("emoji"))
 	in := &input.StatusLineInput{}
 
 	output := c.Render(in)
@@ -204,6 +207,251 @@ func TestCostDaily_Render_ExcludesYesterdayEntries(t *testing.T) {
 	}
 }
 
+// ============================================================
+// CostPeriod window tests
+// ============================================================
+
+func TestCostPeriod_Render_ConfiguredWindowAndLabel(t *testing.T) {
+	month, bill, label, kind, bogus := "month", "billing:1", "MTD", "cost_period", "fortnight"
+	cfg := &config.Config{Components: map[string]config.ComponentConfig{
+		"cost_monthly": {Window: &month},
+		"cost_weekly":  {Window: &bogus},
+		"cost_bill":    {Type: &kind, Window: &bill, Label: &label},
+	}}
+	s := cost.NewTranscriptScanner(t.TempDir(), cache.New(t.TempDir()))
+	r, ic := render.New(nil), icons.New("emoji")
+
+	if output := NewCostMonthly(r, s, cfg, ic).Render(&input.StatusLineInput{}); !strings.Contains(output, "MONTH") {
+		t.Errorf("expected the calendar month label, got: %s", output)
+	}
+	if output := NewCostWeekly(r, s, cfg, ic).Render(&input.StatusLineInput{}); !strings.Contains(output, "7DAY") {
+		t.Errorf("expected an invalid window to fall back to 7 days, got: %s", output)
+	}
+	c := NewCostPeriod(r, s, cfg, ic, "cost_bill")
+	if c.Name() != "cost_bill" {
+		t.Errorf("expected 'cost_bill', got %q", c.Name())
+	}
+	if output := c.Render(&input.StatusLineInput{}); !strings.Contains(output, "MTD") || !strings.Contains(output, "$0.00") {
+		t.Errorf("expected the custom label, got: %s", output)
+	}
+}
+
+func TestCostPeriod_Render_CalendarWeekExcludesLastWeek(t *testing.T) {
+	now := time.Now()
+	weekStart := cost.Window{Calendar: cost.Week}.Start(now)
+	line := `{"type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000,"output_tokens":500,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"%s"}` + "\n"
+	projectsDir := t.TempDir()
+	projDir := filepath.Join(projectsDir, "-Users-test")
+	_ = os.MkdirAll(projDir, 0755)
+	_ = os.WriteFile(filepath.Join(projDir, "session.jsonl"), []byte(
+		fmt.Sprintf(line, now.Format(time.RFC3339Nano))+
+			fmt.Sprintf(line, weekStart.Add(-time.Hour).Format(time.RFC3339Nano)),
+	), 0644)
+
+	week, kind := "week", "cost_period"
+	cfg := &config.Config{Components: map[string]config.ComponentConfig{
+		"cost_this_week": {Type: &kind, Window: &week},
+	}}
+	s := cost.NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+	output := NewCostPeriod(render.New(nil), s, cfg, icons.New("emoji"), "cost_this_week").Render(&input.StatusLineInput{})
+	if !strings.Contains(output, "WEEK") || !strings.Contains(output, "$0.02") {
+		t.Errorf("expected only this week's request, got: %s", output)
+	}
+}
+
+func TestBudgetLimit_MatchesWindow(t *testing.T) {
+	limits := config.BudgetLimits{Daily: 1, Weekly: 7, Monthly: 30}
+	tests := []struct {
+		window cost.Window
+		want   float64
+	}{
+		{cost.Window{Calendar: cost.Day}, 1},
+		{cost.Window{Calendar: cost.Week}, 7},
+		{cost.Window{Calendar: cost.Month}, 30},
+	}
+	for _, tt := range tests {
+		if got := budgetLimit(tt.window)(limits); got != tt.want {
+			t.Errorf("budgetLimit(%s) = %v, want %v", tt.window, got, tt.want)
+		}
+	}
+	for _, w := range []cost.Window{
+		{Rolling: 7 * 24 * time.Hour},
+		{Rolling: 30 * 24 * time.Hour},
+		{Rolling: 48 * time.Hour},
+		{BillingDay: 15},
+	} {
+		if budgetLimit(w) != nil {
+			t.Errorf("expected no budget limit for %s, which no limit covers", w)
+		}
+	}
+}
+
+// ============================================================
+// CostProject tests
+// ============================================================
+
+func TestCostProject_Name(t *testing.T) {
+	r := render.New(nil)
+	ca := cache.New(t.TempDir())
+	s := cost.NewTranscriptScanner(t.TempDir(), ca)
+	c := NewCostProject(r, s, icons.New("emoji"))
+
+	if c.Name() != "cost_project" {
+		t.Errorf("expected 'cost_project', got %q", c.Name())
+	}
+}
+
+func TestCostProject_Render_OnlyCountsCurrentProject(t *testing.T) {
+	projectsDir := t.TempDir()
+	ts := time.Now().Add(-1 * time.Minute).Format(time.RFC3339Nano)
+	for _, dir := range []string{"-Users-test-app", "-Users-test-other"} {
+		_ = os.MkdirAll(filepath.Join(projectsDir, dir), 0755)
+		_ = os.WriteFile(filepath.Join(projectsDir, dir, "session.jsonl"), []byte(
+			`{"type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":100000,"output_tokens":50000,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"`+ts+`"}`+"\n",
+		), 0644)
+	}
+
+	r := render.New(nil)
+	ca := cache.New(t.TempDir())
+	s := cost.NewTranscriptScanner(projectsDir, ca)
+	c := NewCostProject(r, s, icons.New("emoji"))
+	in := &input.StatusLineInput{}
+	in.Workspace.CurrentDir = "/Users/test/app/web"
+	in.Workspace.ProjectDir = "/Users/test/app"
+
+	// Opus: (100000*5+50000*25)/1M = 1.75 per project
+	output := c.Render(in)
+	if strings.Count(output, "$1.75") != 3 {
+		t.Errorf("expected $1.75 for today, 7 and 30 days, got: %s", output)
+	}
+	if !strings.Contains(output, "app") {
+		t.Errorf("expected project name in output, got: %s", output)
+	}
+}
+
+func TestCostProject_Render_NoWorkspace(t *testing.T) {
+	r := render.New(nil)
+	ca := cache.New(t.TempDir())
+	s := cost.NewTranscriptScanner(t.TempDir(), ca)
+	c := NewCostProject(r, s, icons.New("emoji"))
+
+	if output := c.Render(&input.StatusLineInput{}); output != "" {
+		t.Errorf("expected empty output without a workspace, got: %s", output)
+	}
+}
+
+// ============================================================
+// CostModels tests
+// ============================================================
+
+func TestCostModels_Name(t *testing.T) {
+	cfg := &config.Config{Components: make(map[string]config.ComponentConfig)}
+	s := cost.NewTranscriptScanner(t.TempDir(), cache.New(t.TempDir()))
+	c := NewCostModels(render.New(nil), s, cfg, icons.New("emoji"))
+
+	if c.Name() != "cost_models" {
+		t.Errorf("expected 'cost_models', got %q", c.Name())
+	}
+}
+
+func TestCostModels_Render_GroupsByFamily(t *testing.T) {
+	projectsDir := t.TempDir()
+	projDir := filepath.Join(projectsDir, "-Users-test")
+	_ = os.MkdirAll(projDir, 0755)
+	ts := time.Now().Add(-1 * time.Minute).Format(time.RFC3339Nano)
+	line := func(model string, input int) string {
+		return `{"type":"assistant","message":{"model":"` + model + `","usage":{"input_tokens":` + strconv.Itoa(input) + `,"output_tokens":0,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"` + ts + `"}` + "\n"
+	}
+	_ = os.WriteFile(filepath.Join(projDir, "session.jsonl"), []byte(
+		line("claude-haiku-4-5-20251001", 300000)+ // $0.30
+			line("claude-opus-4-6", 8000000)+ // $40
+			line("claude-opus-4-5-20251101", 200000)+ // $1
+			line("claude-sonnet-5", 3000000), // $9
+	), 0644)
+
+	cfg := &config.Config{Components: make(map[string]config.ComponentConfig)}
+	s := cost.NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+	c := NewCostModels(render.New(nil), s, cfg, icons.New("emoji"))
+
+	output := c.Render(&input.StatusLineInput{})
+	if !strings.Contains(output, "30DAY") {
+		t.Errorf("expected '30DAY' label by default, got: %s", output)
+	}
+	if !strings.Contains(output, "Opus $41 · Sonnet $9.00 · Haiku $0.30") {
+		t.Errorf("expected families by cost, got: %s", output)
+	}
+}
+
+func TestCostModels_Render_Window(t *testing.T) {
+	window := "today"
+	cfg := &config.Config{Components: map[string]config.ComponentConfig{
+		"cost_models": {Window: &window},
+	}}
+	projectsDir := t.TempDir()
+	projDir := filepath.Join(projectsDir, "-Users-test")
+	_ = os.MkdirAll(projDir, 0755)
+	_ = os.WriteFile(filepath.Join(projDir, "session.jsonl"), []byte(
+		`{"type":"assistant","message":{"model":"claude-opus-4-6","usage":{"input_tokens":1000,"output_tokens":0,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"`+time.Now().Add(-1*time.Minute).Format(time.RFC3339Nano)+`"}`+"\n",
+	), 0644)
+
+	s := cost.NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+	c := NewCostModels(render.New(nil), s, cfg, icons.New("emoji"))
+
+	output := c.Render(&input.StatusLineInput{})
+	if !strings.Contains(output, "TODAY") || !strings.Contains(output, "Opus $0.01") {
+		t.Errorf("expected today's Opus cost, got: %s", output)
+	}
+}
+
+func TestCostModels_Render_NoUsage(t *testing.T) {
+	cfg := &config.Config{Components: make(map[string]config.ComponentConfig)}
+	s := cost.NewTranscriptScanner(t.TempDir(), cache.New(t.TempDir()))
+	c := NewCostModels(render.New(nil), s, cfg, icons.New("emoji"))
+
+	if output := c.Render(&input.StatusLineInput{}); output != "" {
+		t.Errorf("expected empty output without usage, got: %s", output)
+	}
+}
+
+// ============================================================
+// CostForecast tests
+// ============================================================
+
+func TestCostForecast_Name(t *testing.T) {
+	s := cost.NewTranscriptScanner(t.TempDir(), cache.New(t.TempDir()))
+	c := NewCostForecast(render.New(nil), s, &config.Config{}, icons.New("emoji"))
+
+	if c.Name() != "cost_forecast" {
+		t.Errorf("expected 'cost_forecast', got %q", c.Name())
+	}
+}
+
+func TestCostForecast_Render(t *testing.T) {
+	r := render.New(nil)
+	s := budgetScanner(t)
+
+	output := NewCostForecast(r, s, &config.Config{}, icons.New("emoji")).Render(appInput())
+	if !strings.Contains(output, r.Dimmed("EOM")) || !strings.HasSuffix(output, " $17.50") {
+		t.Errorf("expected the projection and the month to date, got: %q", output)
+	}
+	if strings.Contains(output, "/") {
+		t.Errorf("expected no budget without a monthly limit, got: %s", output)
+	}
+}
+
+func TestCostForecast_Render_OverMonthlyBudget(t *testing.T) {
+	r := render.New(nil)
+	cfg := &config.Config{Budget: &config.BudgetConfig{BudgetLimits: config.BudgetLimits{Monthly: 10}}}
+
+	// Already $17.50 this month, so the projection is over $10.
+	s := budgetScanner(t)
+	want := r.Red(fmt.Sprintf("$%.2f/$10", s.CalculateForecast().Projected))
+	output := NewCostForecast(r, s, cfg, icons.New("emoji")).Render(appInput())
+	if !strings.Contains(output, want) {
+		t.Errorf("expected the projection in red against the budget, got: %s", output)
+	}
+}
+
 // ============================================================
 // CostLive tests
 // ============================================================
@@ -0,0 +1,55 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/cost"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// CostForecast renders the projected end-of-month spend next to the month
+// to date, e.g. "🔮 EOM $412.30 MTD $180.05". With a monthly [budget] limit
+// the projection is shown against it and colored like the budget component,
+// e.g. "EOM $412.30/$400".
+type CostForecast struct {
+	renderer *render.Renderer
+	scanner  *cost.TranscriptScanner
+	config   *config.Config
+	icons    icons.IconSet
+}
+
+// NewCostForecast creates the month-end forecast component.
+func NewCostForecast(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet) *CostForecast {
+	return &CostForecast{renderer: r, scanner: s, config: cfg, icons: ic}
+}
+
+func (c *CostForecast) Name() string {
+	return "cost_forecast"
+}
+
+func (c *CostForecast) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext reads the forecast through ctx.
+func (c *CostForecast) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	f := ctx.Forecast(c.scanner)
+
+	projected := fmt.Sprintf("$%.2f", f.Projected)
+	if limit := c.config.BudgetLimits().Monthly; limit > 0 {
+		color := budgetColor(c.renderer, f.Projected, limit, c.config.BudgetWarnAt())
+		projected = color(projected + "/" + budgetAmount(limit))
+	}
+
+	return fmt.Sprintf("%s %s %s %s $%.2f",
+		c.icons.Get(icons.Forecast),
+		c.renderer.Dimmed("EOM"),
+		projected,
+		c.renderer.Dimmed("MTD"),
+		f.MonthToDate,
+	)
+}
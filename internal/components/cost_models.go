@@ -0,0 +1,99 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/cost"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// CostModels renders the transcript cost of a window (a rolling 30 days
+// unless the window option sets another, see cost.ParseWindow) split by
+// model family, most expensive first, e.g.
+// "30DAY Opus $41 · Sonnet $9.12 · Haiku $0.30". Returns an empty string
+// when there was no usage in the window.
+type CostModels struct {
+	renderer *render.Renderer
+	scanner  *cost.TranscriptScanner
+	config   *config.Config
+	icons    icons.IconSet
+}
+
+// NewCostModels creates the per-model cost component.
+func NewCostModels(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet) *CostModels {
+	return &CostModels{renderer: r, scanner: s, config: cfg, icons: ic}
+}
+
+func (c *CostModels) Name() string {
+	return "cost_models"
+}
+
+func (c *CostModels) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext reads the window's usage through ctx so other
+// components asking for it in this render reuse the scan.
+func (c *CostModels) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	window := cost.Window{Rolling: 30 * 24 * time.Hour}
+	if w, err := cost.ParseWindow(c.config.GetString("cost_models", "window", "")); err == nil {
+		window = w
+	}
+	usage := ctx.WindowModelUsage(c.scanner, window)
+
+	families := costByFamily(usage)
+	if len(families) == 0 {
+		return ""
+	}
+	parts := make([]string, len(families))
+	for i, f := range families {
+		parts[i] = fmt.Sprintf("%s %s", f.name, compactCost(f.cost))
+	}
+
+	return fmt.Sprintf("%s %s %s",
+		c.icons.Get(icons.Robot),
+		c.renderer.Dimmed(window.Label()),
+		strings.Join(parts, " · "),
+	)
+}
+
+// familyCost is the total cost of one model family.
+type familyCost struct {
+	name string
+	cost float64
+}
+
+// costByFamily sums usage by cost.ModelFamily, most expensive first.
+func costByFamily(usage map[string]cost.Usage) []familyCost {
+	totals := make(map[string]float64)
+	for model, u := range usage {
+		totals[cost.ModelFamily(model)] += u.Cost
+	}
+	families := make([]familyCost, 0, len(totals))
+	for name, total := range totals {
+		families = append(families, familyCost{name, total})
+	}
+	sort.Slice(families, func(i, j int) bool {
+		if families[i].cost != families[j].cost {
+			return families[i].cost > families[j].cost
+		}
+		return families[i].name < families[j].name
+	})
+	return families
+}
+
+// compactCost formats a cost in whole dollars from $10 up, and to the cent
+// below that.
+func compactCost(usd float64) string {
+	if usd >= 10 {
+		return fmt.Sprintf("$%.0f", usd)
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
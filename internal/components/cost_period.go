@@ -4,22 +4,28 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/config"
 	"github.com/h2ik/claude-statusline/internal/cost"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
 	"github.com/h2ik/claude-statusline/internal/render"
 )
 
-// CostPeriod renders a rolling cost total by scanning Claude Code's
-// native JSONL transcript files for a configurable time window.
+// CostPeriod renders a cost total by scanning Claude Code's native JSONL
+// transcript files for a time window: a rolling duration, a calendar day,
+// ISO week, or month, or a billing cycle. The window and label default per
+// component and can be overridden with the window and label options. With
+// budget_color set, the total is colored against the matching [budget]
+// limit.
 type CostPeriod struct {
 	renderer *render.Renderer
 	scanner  *cost.TranscriptScanner
+	config   *config.Config
 	icons    icons.IconSet
 	name     string
-	label    string
 	iconName string
-	duration time.Duration
+	window   cost.Window
 }
 
 func (c *CostPeriod) Name() string {
@@ -27,68 +33,92 @@ func (c *CostPeriod) Name() string {
 }
 
 func (c *CostPeriod) Render(in *input.StatusLineInput) string {
-	total := c.scanner.CalculatePeriod(c.duration)
-
-	return fmt.Sprintf("%s %s $%.2f",
-		c.icons.Get(c.iconName),
-		c.renderer.Dimmed(c.label),
-		total,
-	)
+	return c.RenderWithContext(in, component.NewContext(in, ""))
 }
 
-// NewCostMonthly creates a 30-day rolling cost component.
-func NewCostMonthly(r *render.Renderer, s *cost.TranscriptScanner, ic icons.IconSet) *CostPeriod {
-	return &CostPeriod{
-		renderer: r,
-		scanner:  s,
-		icons:    ic,
-		name:     "cost_monthly",
-		label:    "30DAY",
-		iconName: icons.ChartUp,
-		duration: 30 * 24 * time.Hour,
+// RenderWithContext reads the window's total through ctx so other
+// components asking for the same window in this render reuse the scan.
+func (c *CostPeriod) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	window := c.window
+	if w, err := cost.ParseWindow(c.config.GetString(c.name, "window", "")); err == nil {
+		window = w
 	}
-}
+	total := ctx.WindowCost(c.scanner, window)
 
-// NewCostWeekly creates a 7-day rolling cost component.
-func NewCostWeekly(r *render.Renderer, s *cost.TranscriptScanner, ic icons.IconSet) *CostPeriod {
-	return &CostPeriod{
-		renderer: r,
-		scanner:  s,
-		icons:    ic,
-		name:     "cost_weekly",
-		label:    "7DAY",
-		iconName: icons.ChartBar,
-		duration: 7 * 24 * time.Hour,
-	}
+	return fmt.Sprintf("%s %s %s",
+		c.icons.Get(c.iconName),
+		c.renderer.Dimmed(c.config.GetString(c.name, "label", window.Label())),
+		budgetTotal(c.renderer, c.config, c.name, total, budgetLimit(window)),
+	)
 }
 
-// CostToday renders the cost since midnight local time by scanning
-// Claude Code's native JSONL transcript files.
-type CostToday struct {
-	renderer *render.Renderer
-	scanner  *cost.TranscriptScanner
-	icons    icons.IconSet
+// NewCostPeriod creates a cost component for a [components] entry declaring
+// type = "cost_period", over a rolling 30 days unless it sets a window.
+func NewCostPeriod(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet, name string) *CostPeriod {
+	return newCostPeriod(r, s, cfg, ic, name, icons.ChartUp, cost.Window{Rolling: 30 * 24 * time.Hour})
 }
 
-func (c *CostToday) Name() string {
-	return "cost_daily"
+// NewCostMonthly creates a cost component over a rolling 30 days by default.
+func NewCostMonthly(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet) *CostPeriod {
+	return newCostPeriod(r, s, cfg, ic, "cost_monthly", icons.ChartUp, cost.Window{Rolling: 30 * 24 * time.Hour})
 }
 
-func (c *CostToday) Render(in *input.StatusLineInput) string {
-	total := c.scanner.CalculateToday()
+// NewCostWeekly creates a cost component over a rolling 7 days by default.
+func NewCostWeekly(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet) *CostPeriod {
+	return newCostPeriod(r, s, cfg, ic, "cost_weekly", icons.ChartBar, cost.Window{Rolling: 7 * 24 * time.Hour})
+}
 
-	return fmt.Sprintf("%s %s $%.2f",
-		c.icons.Get(icons.Calendar),
-		c.renderer.Dimmed("TODAY"),
-		total,
-	)
+// NewCostDaily creates a cost component showing cost since midnight local
+// time by default.
+func NewCostDaily(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet) *CostPeriod {
+	return newCostPeriod(r, s, cfg, ic, "cost_daily", icons.Calendar, cost.Window{Calendar: cost.Day})
 }
 
-// NewCostDaily creates a component showing cost since midnight local time.
-func NewCostDaily(r *render.Renderer, s *cost.TranscriptScanner, ic icons.IconSet) *CostToday {
-	return &CostToday{
+func newCostPeriod(r *render.Renderer, s *cost.TranscriptScanner, cfg *config.Config, ic icons.IconSet, name, iconName string, window cost.Window) *CostPeriod {
+	return &CostPeriod{
 		renderer: r,
 		scanner:  s,
+		config:   cfg,
 		icons:    ic,
+		name:     name,
+		iconName: iconName,
+		window:   window,
+	}
+}
+
+// budgetLimit picks the [budget] limit a window's total is compared with:
+// the limit for the same calendar window, as the budget component shows it.
+// Rolling and billing windows have none, since their totals are not what
+// the limits cover.
+func budgetLimit(w cost.Window) func(config.BudgetLimits) float64 {
+	if w.Rolling > 0 || w.BillingDay > 0 {
+		return nil
+	}
+	switch w.Calendar {
+	case cost.Day:
+		return func(l config.BudgetLimits) float64 { return l.Daily }
+	case cost.Week:
+		return func(l config.BudgetLimits) float64 { return l.Weekly }
+	case cost.Month:
+		return func(l config.BudgetLimits) float64 { return l.Monthly }
+	default:
+		return nil
+	}
+}
+
+// budgetTotal formats a cost total, colored against the [budget] limit that
+// limit picks when the named component has budget_color set and that limit
+// is configured. A nil limit leaves the total uncolored.
+func budgetTotal(r *render.Renderer, cfg *config.Config, name string, total float64, limit func(config.BudgetLimits) float64) string {
+	s := fmt.Sprintf("$%.2f", total)
+	if !cfg.GetBool(name, "budget_color", false) {
+		return s
+	}
+	if limit == nil {
+		return s
+	}
+	if l := limit(cfg.BudgetLimits()); l > 0 {
+		return budgetColor(r, total, l, cfg.BudgetWarnAt())(s)
 	}
+	return s
 }
@@ -0,0 +1,54 @@
+package components
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/cost"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// CostProject renders what the current project has cost today and over the
+// rolling 7 and 30 days, from the transcripts Claude Code stored for it.
+type CostProject struct {
+	renderer *render.Renderer
+	scanner  *cost.TranscriptScanner
+	icons    icons.IconSet
+}
+
+// NewCostProject creates the per-project cost component.
+func NewCostProject(r *render.Renderer, s *cost.TranscriptScanner, ic icons.IconSet) *CostProject {
+	return &CostProject{renderer: r, scanner: s, icons: ic}
+}
+
+func (c *CostProject) Name() string {
+	return "cost_project"
+}
+
+func (c *CostProject) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext shows the costs of Workspace.ProjectDir, or of the
+// current directory when Claude Code did not report a project directory.
+func (c *CostProject) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	dir := in.Workspace.ProjectDir
+	if dir == "" {
+		dir = in.Workspace.CurrentDir
+	}
+	if dir == "" {
+		return ""
+	}
+	pc := ctx.ProjectCost(c.scanner, dir)
+
+	return fmt.Sprintf("%s %s %s $%.2f %s $%.2f %s $%.2f",
+		c.icons.Get(icons.Folder),
+		filepath.Base(dir),
+		c.renderer.Dimmed("TODAY"), pc.Today,
+		c.renderer.Dimmed("7DAY"), pc.Week,
+		c.renderer.Dimmed("30DAY"), pc.Month,
+	)
+}
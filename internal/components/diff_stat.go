@@ -0,0 +1,79 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// DiffStat renders what is uncommitted on disk according to
+// `git diff --numstat HEAD`: files changed and lines added/removed. With
+// show_session enabled it also shows Claude Code's own session line totals,
+// so edits that were reverted or made outside Claude stand out.
+// Returns an empty string outside a git repository, and when there is nothing
+// to show.
+type DiffStat struct {
+	renderer *render.Renderer
+	config   *config.Config
+	icons    icons.IconSet
+}
+
+// NewDiffStat creates a new DiffStat component with the given renderer and config.
+func NewDiffStat(r *render.Renderer, cfg *config.Config, ic icons.IconSet) *DiffStat {
+	return &DiffStat{renderer: r, config: cfg, icons: ic}
+}
+
+// Name returns the component identifier used for registry lookup.
+func (c *DiffStat) Name() string {
+	return "diff_stat"
+}
+
+// Render produces the diff stat string from the given input.
+func (c *DiffStat) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext produces the diff stat string using the shared git state
+// from ctx.
+func (c *DiffStat) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	d := ctx.DiffStat()
+	if d == nil {
+		return ""
+	}
+	return c.format(d, in)
+}
+
+// format renders d, followed by the session totals from in when show_session
+// is enabled.
+func (c *DiffStat) format(d *git.DiffStat, in *input.StatusLineInput) string {
+	showSession := c.config.GetBool("diff_stat", "show_session", false)
+	sessionLines := in.Cost.TotalLinesAdded + in.Cost.TotalLinesRemoved
+	if d.Files == 0 && (!showSession || sessionLines == 0) {
+		return ""
+	}
+
+	files := "files"
+	if d.Files == 1 {
+		files = "file"
+	}
+	output := fmt.Sprintf("%s %s %s %s",
+		c.icons.Get(icons.Diff),
+		c.renderer.Text(fmt.Sprintf("%d %s", d.Files, files)),
+		c.renderer.Green(fmt.Sprintf("+%d", d.Added)),
+		c.renderer.Red(fmt.Sprintf("-%d", d.Removed)),
+	)
+
+	if showSession {
+		output += fmt.Sprintf(" │ %s %s %s",
+			c.renderer.Dimmed("Claude:"),
+			c.renderer.Green(fmt.Sprintf("+%d", in.Cost.TotalLinesAdded)),
+			c.renderer.Red(fmt.Sprintf("-%d", in.Cost.TotalLinesRemoved)),
+		)
+	}
+	return output
+}
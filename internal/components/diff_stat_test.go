@@ -0,0 +1,50 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+func TestDiffStat_NoRepo(t *testing.T) {
+	c := NewDiffStat(render.New(nil), config.DefaultConfig(), icons.New("emoji"))
+	if c.Name() != "diff_stat" {
+		t.Errorf("expected 'diff_stat', got %s", c.Name())
+	}
+
+	in := &input.StatusLineInput{Workspace: input.Workspace{CurrentDir: t.TempDir()}}
+	if got := c.Render(in); got != "" {
+		t.Errorf("expected empty output outside a repo, got %q", got)
+	}
+}
+
+func TestDiffStat_Format(t *testing.T) {
+	ic := icons.New("emoji")
+	prefix := ic.Get(icons.Diff) + " "
+	in := &input.StatusLineInput{}
+	in.Cost.TotalLinesAdded = 130
+	in.Cost.TotalLinesRemoved = 40
+
+	c := NewDiffStat(render.New(nil), config.DefaultConfig(), ic)
+	if got := render.StripANSI(c.format(&git.DiffStat{Files: 3, Added: 120, Removed: 45}, in)); got != prefix+"3 files +120 -45" {
+		t.Errorf("unexpected output %q", got)
+	}
+	if got := render.StripANSI(c.format(&git.DiffStat{Files: 1, Added: 2}, in)); got != prefix+"1 file +2 -0" {
+		t.Errorf("unexpected output %q", got)
+	}
+	if got := c.format(&git.DiffStat{}, in); got != "" {
+		t.Errorf("expected empty output for a clean tree, got %q", got)
+	}
+
+	show := true
+	cfg := config.DefaultConfig()
+	cfg.Components["diff_stat"] = config.ComponentConfig{ShowSession: &show}
+	c = NewDiffStat(render.New(nil), cfg, ic)
+	if got := render.StripANSI(c.format(&git.DiffStat{}, in)); got != prefix+"0 files +0 -0 │ Claude: +130 -40" {
+		t.Errorf("expected session totals beside a clean tree, got %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// GitRemote renders the upstream tracking branch, ahead/behind counts, and
+// the remote repository (host icon plus owner/repo) for the current branch.
+// Everything comes from local refs and .git/config; nothing is fetched.
+// Returns an empty string when the branch has no upstream.
+type GitRemote struct {
+	renderer *render.Renderer
+	icons    icons.IconSet
+}
+
+// NewGitRemote creates a new GitRemote component with the given renderer.
+func NewGitRemote(r *render.Renderer, ic icons.IconSet) *GitRemote {
+	return &GitRemote{renderer: r, icons: ic}
+}
+
+// Name returns the component identifier used for registry lookup.
+func (c *GitRemote) Name() string {
+	return "git_remote"
+}
+
+// Render produces the remote string from the given input.
+func (c *GitRemote) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext produces the remote string using the shared git snapshot
+// from ctx.
+func (c *GitRemote) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	snap := ctx.Git()
+	if !snap.IsRepo || snap.Upstream == "" {
+		return ""
+	}
+
+	parts := []string{c.remoteLabel(snap.Remote), c.renderer.Mauve(snap.Upstream)}
+	if snap.Ahead > 0 {
+		parts = append(parts, c.renderer.Teal(fmt.Sprintf("↑%d", snap.Ahead)))
+	}
+	if snap.Behind > 0 {
+		parts = append(parts, c.renderer.Teal(fmt.Sprintf("↓%d", snap.Behind)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// remoteLabel renders the host icon and repository path. Well-known hosts
+// show owner/repo; self-hosted servers are prefixed with their host name.
+// An upstream on a local branch or path shows just the link icon.
+func (c *GitRemote) remoteLabel(r *git.Remote) string {
+	if r == nil || r.Host == "" {
+		return c.icons.Get(icons.Link)
+	}
+
+	icon := hostIcon(r.Host)
+	path := r.Repo
+	if r.Owner != "" {
+		path = r.Owner + "/" + r.Repo
+	}
+	if icon == icons.GitServer {
+		path = r.Host + "/" + path
+	}
+	return fmt.Sprintf("%s %s", c.icons.Get(icon), c.renderer.Blue(path))
+}
+
+// hostIcon picks the icon for a remote host, recognizing GitHub, GitLab, and
+// Bitbucket (including their enterprise and self-managed domains by name).
+func hostIcon(host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case strings.Contains(host, "github"):
+		return icons.GitHub
+	case strings.Contains(host, "gitlab"):
+		return icons.GitLab
+	case strings.Contains(host, "bitbucket"):
+		return icons.Bitbucket
+	default:
+		return icons.GitServer
+	}
+}
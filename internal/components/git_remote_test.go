@@ -0,0 +1,47 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+func TestGitRemote_NoRepo(t *testing.T) {
+	c := NewGitRemote(render.New(nil), icons.New("emoji"))
+	if c.Name() != "git_remote" {
+		t.Errorf("expected 'git_remote', got %s", c.Name())
+	}
+
+	in := &input.StatusLineInput{Workspace: input.Workspace{CurrentDir: t.TempDir()}}
+	if got := c.Render(in); got != "" {
+		t.Errorf("expected empty output outside a repo, got %q", got)
+	}
+}
+
+func TestGitRemote_RemoteLabel(t *testing.T) {
+	ic := icons.New("emoji")
+	c := NewGitRemote(render.New(nil), ic)
+
+	tests := []struct {
+		name   string
+		remote *git.Remote
+		want   string
+	}{
+		{"github", &git.Remote{Host: "github.com", Owner: "h2ik", Repo: "claude-statusline"}, ic.Get(icons.GitHub) + " h2ik/claude-statusline"},
+		{"gitlab subgroup", &git.Remote{Host: "gitlab.com", Owner: "group/sub", Repo: "project"}, ic.Get(icons.GitLab) + " group/sub/project"},
+		{"bitbucket", &git.Remote{Host: "bitbucket.org", Owner: "team", Repo: "repo"}, ic.Get(icons.Bitbucket) + " team/repo"},
+		{"self-hosted", &git.Remote{Host: "git.example.com", Owner: "team", Repo: "repo"}, ic.Get(icons.GitServer) + " git.example.com/team/repo"},
+		{"local path", &git.Remote{Owner: "srv", Repo: "repo"}, ic.Get(icons.Link)},
+		{"local branch", nil, ic.Get(icons.Link)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render.StripANSI(c.remoteLabel(tt.remote)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// defaultSubjectLength is how many characters of the commit subject are shown
+// when subject_length is not configured.
+const defaultSubjectLength = 40
+
+// recentCommitAge is how young a commit must be for its age to be
+// highlighted, so a fresh commit by Claude stands out.
+const recentCommitAge = 15 * time.Minute
+
+// LastCommit renders the HEAD commit: short SHA, relative age, author
+// initials, truncated subject, and the nearest tag (with the number of
+// commits since it), e.g. "📌 a1b2c3d 12m ago JD Fix parser… 🏷️ v1.2+3".
+// Returns an empty string outside a git repository or before the first commit.
+type LastCommit struct {
+	renderer *render.Renderer
+	config   *config.Config
+	icons    icons.IconSet
+}
+
+// NewLastCommit creates a new LastCommit component with the given renderer and config.
+func NewLastCommit(r *render.Renderer, cfg *config.Config, ic icons.IconSet) *LastCommit {
+	return &LastCommit{renderer: r, config: cfg, icons: ic}
+}
+
+// Name returns the component identifier used for registry lookup.
+func (c *LastCommit) Name() string {
+	return "last_commit"
+}
+
+// Render produces the last-commit string from the given input.
+func (c *LastCommit) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext produces the last-commit string using the shared git
+// state from ctx.
+func (c *LastCommit) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	commit := ctx.LastCommit()
+	if commit == nil {
+		return ""
+	}
+	return c.format(commit, time.Now())
+}
+
+// format renders commit with its age measured at now.
+func (c *LastCommit) format(commit *git.Commit, now time.Time) string {
+	age := now.Sub(commit.Time)
+	ageColor := c.renderer.Dimmed
+	if age < recentCommitAge {
+		ageColor = c.renderer.Green
+	}
+
+	parts := []string{
+		c.icons.Get(icons.Commit),
+		c.renderer.Mauve(commit.ShortHash),
+		ageColor(relativeAge(age)),
+	}
+	if initials := authorInitials(commit.Author); initials != "" {
+		parts = append(parts, c.renderer.Teal(initials))
+	}
+
+	maxLen := c.config.GetInt("last_commit", "subject_length", defaultSubjectLength)
+	if subject := truncate(commit.Subject, maxLen); subject != "" {
+		parts = append(parts, c.renderer.Text(subject))
+	}
+
+	if commit.Tag != "" {
+		tag := commit.Tag
+		if commit.TagDistance > 0 {
+			tag = fmt.Sprintf("%s+%d", tag, commit.TagDistance)
+		}
+		parts = append(parts, c.icons.Get(icons.Tag)+" "+c.renderer.Blue(tag))
+	}
+	return strings.Join(parts, " ")
+}
+
+// relativeAge renders a duration as a compact age such as "12m ago".
+func relativeAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d/(30*24*time.Hour)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d/(365*24*time.Hour)))
+	}
+}
+
+// authorInitials returns the upper-cased first letters of the first and last
+// words of name, e.g. "Jane Q. Doe" -> "JD".
+func authorInitials(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+	first := []rune(words[0])[0]
+	if len(words) == 1 {
+		return string(unicode.ToUpper(first))
+	}
+	last := []rune(words[len(words)-1])[0]
+	return string([]rune{unicode.ToUpper(first), unicode.ToUpper(last)})
+}
+
+// truncate shortens s to at most n runes, ending with "…" when cut. A
+// non-positive n yields "".
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,85 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+func TestLastCommit_NoRepo(t *testing.T) {
+	c := NewLastCommit(render.New(nil), config.DefaultConfig(), icons.New("emoji"))
+	if c.Name() != "last_commit" {
+		t.Errorf("expected 'last_commit', got %s", c.Name())
+	}
+
+	in := &input.StatusLineInput{Workspace: input.Workspace{CurrentDir: t.TempDir()}}
+	if got := c.Render(in); got != "" {
+		t.Errorf("expected empty output outside a repo, got %q", got)
+	}
+}
+
+func TestLastCommit_Format(t *testing.T) {
+	ic := icons.New("emoji")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	commit := &git.Commit{
+		ShortHash:   "a1b2c3d",
+		Time:        now.Add(-12 * time.Minute),
+		Author:      "Jane Q. Doe",
+		Subject:     "Fix the transcript parser for long sessions",
+		Tag:         "v1.2",
+		TagDistance: 3,
+	}
+
+	c := NewLastCommit(render.New(nil), config.DefaultConfig(), ic)
+	want := ic.Get(icons.Commit) + " a1b2c3d 12m ago JD Fix the transcript parser for long sess… " + ic.Get(icons.Tag) + " v1.2+3"
+	if got := render.StripANSI(c.format(commit, now)); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	short := 0
+	cfg := config.DefaultConfig()
+	cfg.Components["last_commit"] = config.ComponentConfig{SubjectLength: &short}
+	commit.Tag = ""
+	want = ic.Get(icons.Commit) + " a1b2c3d 12m ago JD"
+	if got := render.StripANSI(NewLastCommit(render.New(nil), cfg, ic).format(commit, now)); got != want {
+		t.Errorf("subject_length = 0: got %q, want %q", got, want)
+	}
+}
+
+func TestRelativeAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{12 * time.Minute, "12m ago"},
+		{5 * time.Hour, "5h ago"},
+		{3 * 24 * time.Hour, "3d ago"},
+		{65 * 24 * time.Hour, "2mo ago"},
+		{800 * 24 * time.Hour, "2y ago"},
+	}
+	for _, tt := range tests {
+		if got := relativeAge(tt.d); got != tt.want {
+			t.Errorf("relativeAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorInitials(t *testing.T) {
+	tests := map[string]string{
+		"Jane Q. Doe": "JD",
+		"ada":         "A",
+		"":            "",
+		"émile zola":  "ÉZ",
+	}
+	for name, want := range tests {
+		if got := authorInitials(name); got != want {
+			t.Errorf("authorInitials(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -6,15 +6,20 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/h2ik/claude-statusline/internal/component"
 	"github.com/h2ik/claude-statusline/internal/config"
 	"github.com/h2ik/claude-statusline/internal/git"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
 	"github.com/h2ik/claude-statusline/internal/render"
+	"github.com/h2ik/claude-statusline/internal/vcs"
 )
 
 // RepoInfo renders repository information for the status line: directory path
-// (with ~ notation), git branch, clean/dirty status, and worktree indicator.
+// (with ~ notation), git branch (or detached tag/hash), in-progress operation
+// such as "REBASE 3/7", clean/dirty status, ahead/behind and change counts,
+// and worktree indicator. Jujutsu and Mercurial working copies show their
+// bookmark or branch, change ID, and clean/dirty/conflicted status instead.
 type RepoInfo struct {
 	renderer *render.Renderer
 	config   *config.Config
@@ -33,6 +38,12 @@ func (c *RepoInfo) Name() string {
 
 // Render produces the repo info string from the given input.
 func (c *RepoInfo) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext produces the repo info string using the shared git
+// snapshot from ctx.
+func (c *RepoInfo) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
 	dir := in.Workspace.CurrentDir
 
 	homeDir, _ := os.UserHomeDir()
@@ -43,41 +54,122 @@ func (c *RepoInfo) Render(in *input.StatusLineInput) string {
 		displayDir = compressPath(displayDir, in.Workspace.ProjectDir)
 	}
 
-	if !git.IsGitRepo(dir) {
+	repo := ctx.VCS()
+	if repo == nil {
 		return c.renderer.Blue(displayDir)
 	}
-
-	branch, err := git.GetBranch(dir)
-	if err != nil {
-		return c.renderer.Blue(displayDir)
+	if repo.Kind() != vcs.Git {
+		return c.formatState(displayDir, repo.Kind(), repo.State())
 	}
 
-	clean, err := git.IsClean(dir)
-	if err != nil {
-		clean = false
+	snap := ctx.Git()
+	if !snap.IsRepo || snap.Branch == "" {
+		return c.renderer.Blue(displayDir)
 	}
 
+	// A status call that hit the git timeout says nothing about the working
+	// tree, so it gets its own indicator rather than passing for dirty.
 	statusIcon := c.icons.Get(icons.CheckMark)
 	statusColor := c.renderer.Green
-	if !clean {
+	switch {
+	case snap.StatusTimedOut():
+		statusIcon = c.icons.Get(icons.Hourglass) + "?"
+		statusColor = c.renderer.Peach
+	case !snap.Clean():
 		statusIcon = c.icons.Get(icons.Folder)
 		statusColor = c.renderer.Yellow
 	}
 
-	isWT, wtName, _ := git.IsWorktree(dir)
+	detail := ""
+	if c.config.GetBool("repo_info", "show_detail", true) && snap.StatusErr == nil {
+		if d := c.statusDetail(&snap.Status); d != "" {
+			detail = " " + d
+		}
+	}
+
+	// A detached HEAD shows its tag or short hash instead of "HEAD"; during a
+	// rebase, the branch being rebased is more useful than either.
+	branch := snap.Branch
+	if snap.Head != "" {
+		branch = snap.Head
+	}
+	opIndicator := ""
+	if op := snap.Operation; op != nil {
+		if op.Branch != "" {
+			branch = op.Branch
+		}
+		opIndicator = " " + c.renderer.Red(op.String())
+	}
+
 	wtIndicator := ""
-	if isWT && wtName != "" {
-		wtIndicator = c.renderer.Teal(fmt.Sprintf(" [WT:%s]", wtName))
+	if snap.IsWorktree && snap.WorktreeName != "" {
+		wtIndicator = c.renderer.Teal(fmt.Sprintf(" [WT:%s]", snap.WorktreeName))
 	}
 
-	return fmt.Sprintf("%s %s %s%s",
+	return fmt.Sprintf("%s %s%s %s%s%s",
 		c.renderer.Blue(displayDir),
 		c.renderer.Mauve(fmt.Sprintf("(%s)", branch)),
+		opIndicator,
 		statusColor(statusIcon),
+		detail,
 		wtIndicator,
 	)
 }
 
+// formatState renders a non-git working copy from its common state, e.g.
+// "~/src/app (main) jj:kxqpzmnv ✅". A state that could not be read at all
+// shows just the path, plus the timeout indicator if jj or hg was too slow.
+func (c *RepoInfo) formatState(displayDir string, kind vcs.Kind, st *vcs.State) string {
+	parts := []string{c.renderer.Blue(displayDir)}
+	if st.Branch == "" && st.ChangeID == "" {
+		if st.TimedOut() {
+			parts = append(parts, c.renderer.Peach(c.icons.Get(icons.Hourglass)+"?"))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	if st.Branch != "" {
+		parts = append(parts, c.renderer.Mauve(fmt.Sprintf("(%s)", st.Branch)))
+	}
+	if st.ChangeID != "" {
+		parts = append(parts, c.renderer.Teal(fmt.Sprintf("%s:%s", kind, st.ChangeID)))
+	}
+
+	switch {
+	case st.TimedOut():
+		parts = append(parts, c.renderer.Peach(c.icons.Get(icons.Hourglass)+"?"))
+	case st.Err != nil || st.Dirty:
+		parts = append(parts, c.renderer.Yellow(c.icons.Get(icons.Folder)))
+	default:
+		parts = append(parts, c.renderer.Green(c.icons.Get(icons.CheckMark)))
+	}
+	if st.Conflicted {
+		parts = append(parts, c.renderer.Red("conflict"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// statusDetail renders the non-zero upstream and change counts from a git
+// status, e.g. "↑2 ↓1 +3 ~4 ?1". Returns "" when every count is zero.
+func (c *RepoInfo) statusDetail(s *git.Status) string {
+	var parts []string
+	add := func(n int, symbol string, color func(string) string) {
+		if n > 0 {
+			parts = append(parts, color(fmt.Sprintf("%s%d", symbol, n)))
+		}
+	}
+
+	add(s.Ahead, "↑", c.renderer.Teal)
+	add(s.Behind, "↓", c.renderer.Teal)
+	add(s.Conflicted, "!", c.renderer.Red)
+	add(s.Staged, "+", c.renderer.Green)
+	add(s.Unstaged, "~", c.renderer.Yellow)
+	add(s.Untracked, "?", c.renderer.Peach)
+	add(s.Stashes, "$", c.renderer.Dimmed)
+
+	return strings.Join(parts, " ")
+}
+
 // compressPath applies Fish-style path compression. Intermediate directory
 // segments above the repo root are shortened to their first character.
 // The repo root name and any subdirectories within it are kept in full.
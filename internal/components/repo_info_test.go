@@ -1,15 +1,22 @@
 package components
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/h2ik/claude-statusline/internal/command"
 	"github.com/h2ik/claude-statusline/internal/config"
+	"github.com/h2ik/claude-statusline/internal/git"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
 	"github.com/h2ik/claude-statusline/internal/render"
+	"github.com/h2ik/claude-statusline/internal/vcs"
 )
 
 func TestRepoInfo_Render(t *testing.T) {
@@ -140,3 +147,74 @@ func TestCompressPath(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoInfo_StatusDetail(t *testing.T) {
+	c := NewRepoInfo(render.New(nil), config.DefaultConfig(), icons.New("emoji"))
+
+	got := render.StripANSI(c.statusDetail(&git.Status{
+		Ahead: 2, Behind: 1, Staged: 3, Unstaged: 4, Untracked: 1,
+	}))
+	if got != "↑2 ↓1 +3 ~4 ?1" {
+		t.Errorf("expected '↑2 ↓1 +3 ~4 ?1', got %q", got)
+	}
+
+	if got := c.statusDetail(&git.Status{}); got != "" {
+		t.Errorf("expected empty detail for clean status, got %q", got)
+	}
+}
+
+func TestRepoInfo_StatusUnknownOnTimeout(t *testing.T) {
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	command.SetTimeout(time.Nanosecond)
+	t.Cleanup(func() { command.SetTimeout(command.DefaultTimeout) })
+
+	ic := icons.New("emoji")
+	c := NewRepoInfo(render.New(nil), config.DefaultConfig(), ic)
+	got := render.StripANSI(c.Render(&input.StatusLineInput{
+		Workspace: input.Workspace{CurrentDir: dir},
+	}))
+
+	if !strings.Contains(got, ic.Get(icons.Hourglass)+"?") {
+		t.Errorf("expected status-unknown indicator, got %q", got)
+	}
+	if strings.Contains(got, ic.Get(icons.Folder)) {
+		t.Errorf("timed-out status should not render as dirty, got %q", got)
+	}
+}
+
+func TestRepoInfo_FormatState(t *testing.T) {
+	ic := icons.New("emoji")
+	c := NewRepoInfo(render.New(nil), config.DefaultConfig(), ic)
+
+	tests := []struct {
+		name string
+		kind vcs.Kind
+		st   vcs.State
+		want string
+	}{
+		{"jj clean", vcs.Jujutsu, vcs.State{Branch: "main", ChangeID: "kxqpzmnv"},
+			"~/app (main) jj:kxqpzmnv " + ic.Get(icons.CheckMark)},
+		{"jj conflicted", vcs.Jujutsu, vcs.State{ChangeID: "kxqpzmnv", Dirty: true, Conflicted: true},
+			"~/app jj:kxqpzmnv " + ic.Get(icons.Folder) + " conflict"},
+		{"hg dirty", vcs.Mercurial, vcs.State{Branch: "default", ChangeID: "1a2b3c4d5e6f", Dirty: true},
+			"~/app (default) hg:1a2b3c4d5e6f " + ic.Get(icons.Folder)},
+		{"unreadable", vcs.Mercurial, vcs.State{Err: errors.New("hg: not found")},
+			"~/app"},
+		{"timed out", vcs.Jujutsu, vcs.State{Err: fmt.Errorf("jj log: %w", command.ErrTimeout)},
+			"~/app " + ic.Get(icons.Hourglass) + "?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := render.StripANSI(c.formatState("~/app", tt.kind, &tt.st))
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/h2ik/claude-statusline/internal/cache"
+	"github.com/h2ik/claude-statusline/internal/config"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
 	"github.com/h2ik/claude-statusline/internal/render"
+	"github.com/h2ik/claude-statusline/internal/vcs"
 )
 
 // ============================================================
@@ -126,7 +129,7 @@ func TestModelInfo_Render_BedrockARN_ReturnsEmpty(t *testing.T) {
 
 func TestCommits_Name(t *testing.T) {
 	r := render.New(nil)
-	c := NewCommits(r, icons.New("emoji"))
+	c := NewCommits(r, config.DefaultConfig(), icons.New("emoji"))
 
 	if c.Name() != "commits" {
 		t.Errorf("expected 'commits', got %q", c.Name())
@@ -135,7 +138,7 @@ func TestCommits_Name(t *testing.T) {
 
 func TestCommits_Render_NonGitDir(t *testing.T) {
 	r := render.New(nil)
-	c := NewCommits(r, icons.New("emoji"))
+	c := NewCommits(r, config.DefaultConfig(), icons.New("emoji"))
 
 	in := &input.StatusLineInput{
 		Workspace: input.Workspace{
@@ -149,6 +152,28 @@ func TestCommits_Render_NonGitDir(t *testing.T) {
 	}
 }
 
+func TestCommitWindow(t *testing.T) {
+	// Thursday afternoon.
+	now := time.Date(2025, 6, 5, 15, 30, 45, 0, time.Local)
+
+	tests := []struct {
+		window    string
+		wantSince time.Time
+		wantLabel string
+	}{
+		{"today", time.Date(2025, 6, 5, 0, 0, 0, 0, time.Local), "Commits:"},
+		{"", time.Date(2025, 6, 5, 0, 0, 0, 0, time.Local), "Commits:"},
+		{"24h", time.Date(2025, 6, 4, 15, 30, 0, 0, time.Local), "Commits (24h):"},
+		{"week", time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local), "Commits (week):"},
+	}
+	for _, tt := range tests {
+		since, label := commitWindow(tt.window, now)
+		if !since.Equal(tt.wantSince) || label != tt.wantLabel {
+			t.Errorf("commitWindow(%q) = (%v, %q), want (%v, %q)", tt.window, since, label, tt.wantSince, tt.wantLabel)
+		}
+	}
+}
+
 // ============================================================
 // Submodules tests
 // ============================================================
@@ -178,6 +203,22 @@ func TestSubmodules_Render_NonGitDir(t *testing.T) {
 	}
 }
 
+func TestSubmodules_StatusDetail(t *testing.T) {
+	r := render.New(nil)
+	c := NewSubmodules(r, icons.New("emoji"))
+
+	got := render.StripANSI(c.statusDetail(&vcs.SubmoduleStatus{
+		Total: 6, Uninitialized: 1, Modified: 2, Conflicted: 1,
+	}))
+	if got != "-1 +2 U1" {
+		t.Errorf("expected '-1 +2 U1', got %q", got)
+	}
+
+	if got := c.statusDetail(&vcs.SubmoduleStatus{Total: 3}); got != "" {
+		t.Errorf("expected no detail when all submodules are in sync, got %q", got)
+	}
+}
+
 // ============================================================
 // VersionInfo tests
 // ============================================================
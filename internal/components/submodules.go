@@ -2,15 +2,19 @@ package components
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/component"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
 	"github.com/h2ik/claude-statusline/internal/render"
+	"github.com/h2ik/claude-statusline/internal/vcs"
 )
 
-// Submodules renders the count of git submodules in the current repo.
-// Returns an empty string if the directory is not a git repo or has no submodules.
+// Submodules renders the number of git submodules (or Mercurial subrepos) in
+// the current repo, followed by how many are uninitialized (-N), checked out
+// at a different commit than the superproject records (+N), or conflicted (UN).
+// Returns an empty string outside a repository or when there are none.
 type Submodules struct {
 	renderer *render.Renderer
 	icons    icons.IconSet
@@ -26,16 +30,44 @@ func (c *Submodules) Name() string {
 	return "submodules"
 }
 
-// Render produces the submodule count string from the given input.
+// Render produces the submodule status string from the given input.
 func (c *Submodules) Render(in *input.StatusLineInput) string {
-	count, err := git.GetSubmoduleCount(in.Workspace.CurrentDir)
-	if err != nil || count == 0 {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext produces the submodule status string using the shared
+// per-render repository state from ctx.
+func (c *Submodules) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	st := ctx.SubmoduleStatus()
+	if st.Total == 0 {
 		return ""
 	}
 
-	return fmt.Sprintf("%s %s%d",
+	out := fmt.Sprintf("%s %s%d",
 		c.icons.Get(icons.Link),
 		c.renderer.Dimmed("SUB:"),
-		count,
+		st.Total,
 	)
+	if detail := c.statusDetail(st); detail != "" {
+		out += " " + detail
+	}
+	return out
+}
+
+// statusDetail renders the non-zero out-of-sync counts, e.g. "-1 +2 U1",
+// using git's own status characters. Returns "" when every submodule is in
+// sync.
+func (c *Submodules) statusDetail(st *vcs.SubmoduleStatus) string {
+	var parts []string
+	add := func(n int, symbol string, color func(string) string) {
+		if n > 0 {
+			parts = append(parts, color(fmt.Sprintf("%s%d", symbol, n)))
+		}
+	}
+
+	add(st.Uninitialized, "-", c.renderer.Dimmed)
+	add(st.Modified, "+", c.renderer.Yellow)
+	add(st.Conflicted, "U", c.renderer.Red)
+
+	return strings.Join(parts, " ")
 }
@@ -0,0 +1,116 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+// maxWorktreeLabels caps how many worktree branches are listed before the
+// rest are summarized as "+N".
+const maxWorktreeLabels = 3
+
+// Worktrees renders an overview of the repository's worktrees: how many
+// there are, the branch checked out in each (current one first, dirty ones
+// marked with *), and how many are locked or prunable, e.g.
+// "🌳 WT:4 main* feat-a feat-b +1 1 locked". Returns an empty string outside
+// a git repository or when the main checkout is the only worktree.
+type Worktrees struct {
+	renderer *render.Renderer
+	icons    icons.IconSet
+}
+
+// NewWorktrees creates a new Worktrees component with the given renderer.
+func NewWorktrees(r *render.Renderer, ic icons.IconSet) *Worktrees {
+	return &Worktrees{renderer: r, icons: ic}
+}
+
+// Name returns the component identifier used for registry lookup.
+func (c *Worktrees) Name() string {
+	return "worktrees"
+}
+
+// Render produces the worktree overview from the given input.
+func (c *Worktrees) Render(in *input.StatusLineInput) string {
+	return c.RenderWithContext(in, component.NewContext(in, ""))
+}
+
+// RenderWithContext produces the worktree overview using the shared git
+// state from ctx.
+func (c *Worktrees) RenderWithContext(in *input.StatusLineInput, ctx *component.Context) string {
+	return c.format(ctx.Worktrees())
+}
+
+// format renders the overview for wts; bare entries are not counted.
+func (c *Worktrees) format(wts []git.Worktree) string {
+	var current, others []git.Worktree
+	locked, prunable := 0, 0
+	for _, wt := range wts {
+		if wt.Bare {
+			continue
+		}
+		if wt.Locked {
+			locked++
+		}
+		if wt.Prunable {
+			prunable++
+		}
+		if wt.Current {
+			current = append(current, wt)
+		} else {
+			others = append(others, wt)
+		}
+	}
+
+	ordered := append(current, others...)
+	if len(ordered) <= 1 {
+		return ""
+	}
+
+	parts := []string{fmt.Sprintf("%s %s%d",
+		c.icons.Get(icons.Worktree),
+		c.renderer.Dimmed("WT:"),
+		len(ordered),
+	)}
+	for i, wt := range ordered {
+		if i == maxWorktreeLabels {
+			parts = append(parts, c.renderer.Dimmed(fmt.Sprintf("+%d", len(ordered)-i)))
+			break
+		}
+		parts = append(parts, c.label(wt))
+	}
+	if locked > 0 {
+		parts = append(parts, c.renderer.Peach(fmt.Sprintf("%d locked", locked)))
+	}
+	if prunable > 0 {
+		parts = append(parts, c.renderer.Red(fmt.Sprintf("%d prunable", prunable)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// label names a worktree by its branch, or short commit hash when detached.
+// The current worktree is highlighted and dirty ones get a trailing *.
+func (c *Worktrees) label(wt git.Worktree) string {
+	name := wt.Branch
+	if name == "" {
+		name = wt.Head
+		if len(name) > 7 {
+			name = name[:7]
+		}
+	}
+
+	if wt.Current {
+		name = c.renderer.Mauve(name)
+	} else {
+		name = c.renderer.Text(name)
+	}
+	if wt.Dirty {
+		name += c.renderer.Yellow("*")
+	}
+	return name
+}
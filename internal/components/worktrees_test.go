@@ -0,0 +1,66 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/h2ik/claude-statusline/internal/git"
+	"github.com/h2ik/claude-statusline/internal/icons"
+	"github.com/h2ik/claude-statusline/internal/input"
+	"github.com/h2ik/claude-statusline/internal/render"
+)
+
+func TestWorktrees_NoRepo(t *testing.T) {
+	c := NewWorktrees(render.New(nil), icons.New("emoji"))
+	if c.Name() != "worktrees" {
+		t.Errorf("expected 'worktrees', got %s", c.Name())
+	}
+
+	in := &input.StatusLineInput{Workspace: input.Workspace{CurrentDir: t.TempDir()}}
+	if got := c.Render(in); got != "" {
+		t.Errorf("expected empty output outside a repo, got %q", got)
+	}
+}
+
+func TestWorktrees_Format(t *testing.T) {
+	ic := icons.New("emoji")
+	c := NewWorktrees(render.New(nil), ic)
+	prefix := ic.Get(icons.Worktree) + " WT:"
+
+	tests := []struct {
+		name string
+		wts  []git.Worktree
+		want string
+	}{
+		{
+			name: "single worktree",
+			wts:  []git.Worktree{{Branch: "main", Current: true}},
+			want: "",
+		},
+		{
+			name: "current first, dirty marked",
+			wts: []git.Worktree{
+				{Branch: "main", Dirty: true},
+				{Branch: "feature", Current: true},
+			},
+			want: prefix + "2 feature main*",
+		},
+		{
+			name: "bare skipped, overflow, locked and prunable",
+			wts: []git.Worktree{
+				{Bare: true},
+				{Branch: "main", Current: true},
+				{Head: "0123456789abcdef", Detached: true, Locked: true},
+				{Branch: "b"},
+				{Branch: "c", Prunable: true},
+			},
+			want: prefix + "4 main 0123456 b +1 1 locked 1 prunable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render.StripANSI(c.format(tt.wts)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
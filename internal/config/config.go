@@ -2,17 +2,110 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/h2ik/claude-statusline/internal/cost"
 )
 
 // Config holds the statusline configuration.
 type Config struct {
 	Layout     Layout                     `toml:"layout"`
 	Components map[string]ComponentConfig `toml:"components"`
+	Git        *GitConfig                 `toml:"git,omitempty"`
+	Pricing    *PricingConfig             `toml:"pricing,omitempty"`
+	Budget     *BudgetConfig              `toml:"budget,omitempty"`
+}
+
+// GitConfig tunes how git is invoked for repository status. A nil *GitConfig
+// (no [git] section) uses the defaults.
+type GitConfig struct {
+	// Timeout bounds each git subprocess, as a Go duration string.
+	Timeout string `toml:"timeout,omitempty"`
+
+	// LargeRepoPaths lists repositories (or parent directories, "~" allowed)
+	// that always use large-repo mode, which skips the untracked-file scan.
+	LargeRepoPaths []string `toml:"large_repo_paths,omitempty"`
+
+	// LargeRepoIndexMB enables large-repo mode automatically when the git
+	// index is at least this many MiB. Zero uses DefaultLargeRepoIndexMB and
+	// a negative value disables auto-detection.
+	LargeRepoIndexMB int `toml:"large_repo_index_mb,omitempty"`
+}
+
+// PricingConfig overrides the built-in model prices used for transcript
+// costs. A nil *PricingConfig (no [pricing] section) keeps the built-ins.
+type PricingConfig struct {
+	// File names a separate TOML file ("~" allowed) with the same keys as
+	// this section, e.g. a rate sheet shared across machines. Entries set
+	// in the section itself win over the file's.
+	File string `toml:"file,omitempty"`
+
+	// Discount multiplies every rate, e.g. 0.8 for a 20% discount. Zero
+	// means no discount.
+	Discount float64 `toml:"discount,omitempty"`
+
+	// Models sets rates for exact model IDs and Prefixes for model ID
+	// prefixes; both take precedence over the built-in table.
+	Models   map[string]ModelRates `toml:"models,omitempty"`
+	Prefixes map[string]ModelRates `toml:"prefixes,omitempty"`
+
+	// Default replaces the Sonnet-tier rates charged for unknown models.
+	Default *ModelRates `toml:"default,omitempty"`
+}
+
+// ModelRates holds USD prices per million tokens. A rate left unset keeps
+// the built-in price for the entry's model ID or prefix.
+type ModelRates struct {
+	Input      *float64 `toml:"input,omitempty"`
+	Output     *float64 `toml:"output,omitempty"`
+	CacheWrite *float64 `toml:"cache_write,omitempty"`
+	CacheRead  *float64 `toml:"cache_read,omitempty"`
+
+	// LongContext replaces these rates for requests whose total input
+	// tokens exceed its threshold. Without it the entry keeps the built-in
+	// tier, if any.
+	LongContext *LongContextRates `toml:"long_context,omitempty"`
+}
+
+// LongContextRates is a premium pricing tier for large requests.
+type LongContextRates struct {
+	Threshold  int     `toml:"threshold"`
+	Input      float64 `toml:"input"`
+	Output     float64 `toml:"output"`
+	CacheWrite float64 `toml:"cache_write"`
+	CacheRead  float64 `toml:"cache_read"`
+}
+
+// BudgetConfig sets USD spending limits that the budget component, and the
+// cost components with budget_color, are colored against. A nil
+// *BudgetConfig (no [budget] section) sets none.
+type BudgetConfig struct {
+	BudgetLimits
+
+	// WarnAt is the fraction of a limit from which spending shows as a
+	// warning. Zero uses DefaultBudgetWarnAt.
+	WarnAt float64 `toml:"warn_at,omitempty"`
+
+	// Projects sets limits for individual project directories ("~" allowed),
+	// counting only that project's transcripts.
+	Projects map[string]BudgetLimits `toml:"projects,omitempty"`
+}
+
+// BudgetLimits are spending limits for calendar windows: today, the ISO
+// week starting Monday, and the calendar month, the month cost_forecast
+// projects. Zero means no limit.
+type BudgetLimits struct {
+	Daily   float64 `toml:"daily,omitempty"`
+	Weekly  float64 `toml:"weekly,omitempty"`
+	Monthly float64 `toml:"monthly,omitempty"`
 }
 
 // Layout defines which components appear on each line.
@@ -22,6 +115,11 @@ type Layout struct {
 	IconStyle string       `toml:"icon_style"`
 	Padding   int          `toml:"padding"`
 	Lines     []LayoutLine `toml:"lines"`
+
+	// ComponentTimeout and RenderBudget are Go duration strings (e.g. "500ms").
+	// Empty values fall back to DefaultComponentTimeout and DefaultRenderBudget.
+	ComponentTimeout string `toml:"component_timeout,omitempty"`
+	RenderBudget     string `toml:"render_budget,omitempty"`
 }
 
 // LayoutLine describes the left and right component groups for a single
@@ -34,13 +132,38 @@ type LayoutLine struct {
 // ComponentConfig holds per-component configuration options.
 // Pointer bools distinguish "not set" from "set to false".
 type ComponentConfig struct {
-	ShowRegion      *bool   `toml:"show_region,omitempty"`
-	ShowTokens      *bool   `toml:"show_tokens,omitempty"`
-	ShowVelocity    *bool   `toml:"show_velocity,omitempty"`
-	ShowCostPerLine *bool   `toml:"show_cost_per_line,omitempty"`
-	PathStyle       *string `toml:"path_style,omitempty"`
+	ShowRegion      *bool    `toml:"show_region,omitempty"`
+	ShowTokens      *bool    `toml:"show_tokens,omitempty"`
+	ShowVelocity    *bool    `toml:"show_velocity,omitempty"`
+	ShowCostPerLine *bool    `toml:"show_cost_per_line,omitempty"`
+	ShowDetail      *bool    `toml:"show_detail,omitempty"`
+	ShowSession     *bool    `toml:"show_session,omitempty"`
+	PathStyle       *string  `toml:"path_style,omitempty"`
+	Timeout         *string  `toml:"timeout,omitempty"`
+	SubjectLength   *int     `toml:"subject_length,omitempty"`
+	AllBranches     *bool    `toml:"all_branches,omitempty"`
+	Window          *string  `toml:"window,omitempty"`
+	Authors         []string `toml:"authors,omitempty"`
+	BudgetColor     *bool    `toml:"budget_color,omitempty"`
+	Type            *string  `toml:"type,omitempty"`
+	Label           *string  `toml:"label,omitempty"`
 }
 
+// Default render deadlines used when the config does not set them.
+const (
+	DefaultComponentTimeout = 3 * time.Second
+	DefaultRenderBudget     = 5 * time.Second
+)
+
+// DefaultLargeRepoIndexMB is the index size from which a repository is
+// treated as large when [git] does not set large_repo_index_mb. A 32 MiB
+// index tracks on the order of a few hundred thousand files.
+const DefaultLargeRepoIndexMB = 32
+
+// DefaultBudgetWarnAt is the share of a budget from which spending shows as
+// a warning when [budget] does not set warn_at.
+const DefaultBudgetWarnAt = 0.8
+
 // legacyLayout mirrors the old flat lines format ([][]string) so we can detect
 // and auto-migrate configs written before left/right support was added.
 type legacyLayout struct {
@@ -244,6 +367,22 @@ func (c *Config) GetBool(component, key string, fallback bool) bool {
 		if comp.ShowCostPerLine != nil {
 			return *comp.ShowCostPerLine
 		}
+	case "show_detail":
+		if comp.ShowDetail != nil {
+			return *comp.ShowDetail
+		}
+	case "show_session":
+		if comp.ShowSession != nil {
+			return *comp.ShowSession
+		}
+	case "all_branches":
+		if comp.AllBranches != nil {
+			return *comp.AllBranches
+		}
+	case "budget_color":
+		if comp.BudgetColor != nil {
+			return *comp.BudgetColor
+		}
 	}
 
 	return fallback
@@ -262,11 +401,320 @@ func (c *Config) GetString(component, key, fallback string) string {
 		if comp.PathStyle != nil {
 			return *comp.PathStyle
 		}
+	case "window":
+		if comp.Window != nil {
+			return *comp.Window
+		}
+	case "type":
+		if comp.Type != nil {
+			return *comp.Type
+		}
+	case "label":
+		if comp.Label != nil {
+			return *comp.Label
+		}
+	}
+
+	return fallback
+}
+
+// GetStrings retrieves a string list from the ComponentConfig for the given
+// component and key name. Returns fallback if the component or key is not set.
+func (c *Config) GetStrings(component, key string, fallback []string) []string {
+	comp, ok := c.Components[component]
+	if !ok {
+		return fallback
+	}
+
+	switch key {
+	case "authors":
+		if comp.Authors != nil {
+			return comp.Authors
+		}
+	}
+
+	return fallback
+}
+
+// GetInt retrieves an integer value from the ComponentConfig for the given
+// component and key name. Returns fallback if the component or key is not set.
+func (c *Config) GetInt(component, key string, fallback int) int {
+	comp, ok := c.Components[component]
+	if !ok {
+		return fallback
+	}
+
+	switch key {
+	case "subject_length":
+		if comp.SubjectLength != nil {
+			return *comp.SubjectLength
+		}
 	}
 
 	return fallback
 }
 
+// ComponentTimeout returns the render deadline for the named component: its
+// own `timeout` if set, else the layout-wide `component_timeout`, else
+// DefaultComponentTimeout. Unparseable values are treated as unset.
+func (c *Config) ComponentTimeout(component string) time.Duration {
+	if comp, ok := c.Components[component]; ok && comp.Timeout != nil {
+		if d, err := time.ParseDuration(*comp.Timeout); err == nil {
+			return d
+		}
+	}
+	if d, err := time.ParseDuration(c.Layout.ComponentTimeout); err == nil {
+		return d
+	}
+	return DefaultComponentTimeout
+}
+
+// RenderBudget returns the overall deadline for rendering every component,
+// falling back to DefaultRenderBudget when unset or unparseable.
+func (c *Config) RenderBudget() time.Duration {
+	if d, err := time.ParseDuration(c.Layout.RenderBudget); err == nil {
+		return d
+	}
+	return DefaultRenderBudget
+}
+
+// GitTimeout returns the per-command git deadline from [git] timeout. ok is
+// false when it is unset or unparseable, leaving the git package's own
+// default in place.
+func (c *Config) GitTimeout() (d time.Duration, ok bool) {
+	d, err := time.ParseDuration(c.gitConfig().Timeout)
+	return d, err == nil
+}
+
+// LargeRepoPaths returns [git] large_repo_paths with a leading "~" expanded
+// to the user's home directory.
+func (c *Config) LargeRepoPaths() []string {
+	var paths []string
+	for _, p := range c.gitConfig().LargeRepoPaths {
+		paths = append(paths, expandHome(p))
+	}
+	return paths
+}
+
+// LargeRepoIndexSize returns the index size in bytes that switches on
+// large-repo mode, or 0 when auto-detection is disabled.
+func (c *Config) LargeRepoIndexSize() int64 {
+	mb := c.gitConfig().LargeRepoIndexMB
+	if mb == 0 {
+		mb = DefaultLargeRepoIndexMB
+	}
+	if mb < 0 {
+		return 0
+	}
+	return int64(mb) << 20
+}
+
+// gitConfig returns the [git] section, or an empty one when it is absent.
+func (c *Config) gitConfig() *GitConfig {
+	if c.Git == nil {
+		return &GitConfig{}
+	}
+	return c.Git
+}
+
+// PricingOverrides returns the effective [pricing] settings: those of the
+// pricing file, if one is named, overlaid with the section's own entries. It
+// returns nil when there is no [pricing] section. A pricing file that cannot
+// be read or parsed is reported alongside the section's own settings.
+func (c *Config) PricingOverrides() (*PricingConfig, error) {
+	if c.Pricing == nil {
+		return nil, nil
+	}
+	section := *c.Pricing
+	if section.File == "" {
+		return &section, nil
+	}
+
+	var file PricingConfig
+	if _, err := toml.DecodeFile(expandHome(section.File), &file); err != nil {
+		return &section, err
+	}
+	merged := file
+	merged.File = section.File
+	if section.Discount != 0 {
+		merged.Discount = section.Discount
+	}
+	if section.Default != nil {
+		merged.Default = section.Default
+	}
+	merged.Models = mergeRates(file.Models, section.Models)
+	merged.Prefixes = mergeRates(file.Prefixes, section.Prefixes)
+	return &merged, nil
+}
+
+// Overrides converts the pricing config into cost overrides. Each entry
+// starts from the built-in price of its model ID or prefix (or the built-in
+// default), and replaces only the rates, and the long-context tier, it sets. A nil pc overrides nothing.
+func (pc *PricingConfig) Overrides() cost.Overrides {
+	if pc == nil {
+		return cost.Overrides{}
+	}
+	table := func(entries map[string]ModelRates) map[string]cost.Pricing {
+		out := make(map[string]cost.Pricing, len(entries))
+		for model, m := range entries {
+			out[model] = m.over(cost.BuiltinPrice(model))
+		}
+		return out
+	}
+
+	o := cost.Overrides{
+		Models:   table(pc.Models),
+		Prefixes: table(pc.Prefixes),
+		Discount: pc.Discount,
+	}
+	if pc.Default != nil {
+		p := pc.Default.over(cost.BuiltinPrice(""))
+		o.Default = &p
+	}
+	return o
+}
+
+// over returns base with the rates m sets replaced.
+func (m ModelRates) over(base cost.Pricing) cost.Pricing {
+	p := cost.Pricing{
+		InputPerMillion:      base.InputPerMillion,
+		OutputPerMillion:     base.OutputPerMillion,
+		CacheWritePerMillion: base.CacheWritePerMillion,
+		CacheReadPerMillion:  base.CacheReadPerMillion,
+		LongContext:          base.LongContext,
+	}
+	set := func(dst *float64, v *float64) {
+		if v != nil {
+			*dst = *v
+		}
+	}
+	set(&p.InputPerMillion, m.Input)
+	set(&p.OutputPerMillion, m.Output)
+	set(&p.CacheWritePerMillion, m.CacheWrite)
+	set(&p.CacheReadPerMillion, m.CacheRead)
+	if lc := m.LongContext; lc != nil {
+		p.LongContext = &cost.Tier{
+			Threshold:            lc.Threshold,
+			InputPerMillion:      lc.Input,
+			OutputPerMillion:     lc.Output,
+			CacheWritePerMillion: lc.CacheWrite,
+			CacheReadPerMillion:  lc.CacheRead,
+		}
+	}
+	return p
+}
+
+// mergeRates returns the entries of base overlaid with those of over.
+func mergeRates(base, over map[string]ModelRates) map[string]ModelRates {
+	if len(base) == 0 {
+		return over
+	}
+	merged := make(map[string]ModelRates, len(base)+len(over))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range over {
+		merged[k] = v
+	}
+	return merged
+}
+
+// expandHome replaces a leading "~" in p with the user's home directory.
+func expandHome(p string) string {
+	homeDir, _ := os.UserHomeDir()
+	if homeDir != "" && (p == "~" || strings.HasPrefix(p, "~/")) {
+		return filepath.Join(homeDir, p[1:])
+	}
+	return p
+}
+
+// BudgetLimits returns the global [budget] limits, all zero without a
+// [budget] section.
+func (c *Config) BudgetLimits() BudgetLimits {
+	if c.Budget == nil {
+		return BudgetLimits{}
+	}
+	return c.Budget.BudgetLimits
+}
+
+// ProjectBudget returns the [budget.projects] limits for the project at dir,
+// all zero when it has none.
+func (c *Config) ProjectBudget(dir string) BudgetLimits {
+	if c.Budget == nil || dir == "" {
+		return BudgetLimits{}
+	}
+	dir = filepath.Clean(dir)
+	for p, limits := range c.Budget.Projects {
+		if filepath.Clean(expandHome(p)) == dir {
+			return limits
+		}
+	}
+	return BudgetLimits{}
+}
+
+// BudgetWarnAt returns [budget] warn_at, or DefaultBudgetWarnAt when unset.
+func (c *Config) BudgetWarnAt() float64 {
+	if c.Budget == nil || c.Budget.WarnAt <= 0 {
+		return DefaultBudgetWarnAt
+	}
+	return c.Budget.WarnAt
+}
+
+// ComponentNames returns every component referenced by the layout, left then
+// right for each line, with duplicates removed.
+func (c *Config) ComponentNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, line := range c.Layout.Lines {
+		for _, group := range [][]string{line.Left, line.Right} {
+			for _, name := range group {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// ComponentsOfType returns the names of the [components] entries declaring
+// the given type, sorted. These are extra instances of a built-in component,
+// e.g. a second cost_period with its own window.
+func (c *Config) ComponentsOfType(kind string) []string {
+	var names []string
+	for name, comp := range c.Components {
+		if comp.Type != nil && *comp.Type == kind {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// windowComponents are the built-in components whose window option is a
+// cost.Window spec. Entries of type cost_period take one too.
+var windowComponents = []string{"cost_daily", "cost_weekly", "cost_monthly", "cost_models"}
+
+// WindowErrors returns an error for each cost component whose window option
+// does not parse, sorted by component name. Such a component falls back to
+// its default window; the errors let the caller warn about it.
+func (c *Config) WindowErrors() []error {
+	names := append(append([]string(nil), windowComponents...), c.ComponentsOfType("cost_period")...)
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		comp, ok := c.Components[name]
+		if !ok || comp.Window == nil {
+			continue
+		}
+		if _, err := cost.ParseWindow(*comp.Window); err != nil {
+			errs = append(errs, fmt.Errorf("components.%s: %w", name, err))
+		}
+	}
+	return errs
+}
+
 // writeConfig writes the configuration to the given path as TOML, creating
 // parent directories as needed.
 func writeConfig(path string, cfg *Config) error {
@@ -3,7 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cost"
 )
 
 func TestLoad_CreatesDefaultWhenMissing(t *testing.T) {
@@ -434,3 +438,332 @@ func TestDefaultPowerlineConfig_HasMochaTheme(t *testing.T) {
 		t.Errorf("DefaultPowerlineConfig should have theme 'catppuccin-mocha', got %q", cfg.Layout.Theme)
 	}
 }
+
+func TestComponentTimeout_Fallbacks(t *testing.T) {
+	custom := "750ms"
+	bogus := "soon"
+	cfg := &Config{
+		Layout: Layout{ComponentTimeout: "1s"},
+		Components: map[string]ComponentConfig{
+			"bedrock_model": {Timeout: &custom},
+			"version_info":  {Timeout: &bogus},
+		},
+	}
+
+	if got := cfg.ComponentTimeout("bedrock_model"); got != 750*time.Millisecond {
+		t.Errorf("expected per-component override 750ms, got %v", got)
+	}
+	if got := cfg.ComponentTimeout("version_info"); got != time.Second {
+		t.Errorf("expected unparseable override to fall back to layout 1s, got %v", got)
+	}
+	if got := cfg.ComponentTimeout("repo_info"); got != time.Second {
+		t.Errorf("expected layout timeout 1s, got %v", got)
+	}
+
+	empty := &Config{}
+	if got := empty.ComponentTimeout("repo_info"); got != DefaultComponentTimeout {
+		t.Errorf("expected default %v, got %v", DefaultComponentTimeout, got)
+	}
+	if got := empty.RenderBudget(); got != DefaultRenderBudget {
+		t.Errorf("expected default budget %v, got %v", DefaultRenderBudget, got)
+	}
+}
+
+func TestComponentNames_DedupesInLayoutOrder(t *testing.T) {
+	cfg := &Config{Layout: Layout{Lines: []LayoutLine{
+		{Left: []string{"repo_info", "model_info"}, Right: []string{"time_display"}},
+		{Left: []string{"model_info", "cost_daily"}},
+	}}}
+
+	got := cfg.ComponentNames()
+	want := []string{"repo_info", "model_info", "time_display", "cost_daily"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("index %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestGitConfig_Fallbacks(t *testing.T) {
+	empty := &Config{}
+	if got, ok := empty.GitTimeout(); ok {
+		t.Errorf("expected no git timeout when unset, got %v", got)
+	}
+	if got := empty.LargeRepoIndexSize(); got != DefaultLargeRepoIndexMB<<20 {
+		t.Errorf("expected default index threshold, got %d", got)
+	}
+
+	cfg := &Config{Git: &GitConfig{
+		Timeout:          "250ms",
+		LargeRepoPaths:   []string{"~/src/mono", "/opt/big"},
+		LargeRepoIndexMB: -1,
+	}}
+	if got, ok := cfg.GitTimeout(); !ok || got != 250*time.Millisecond {
+		t.Errorf("expected 250ms, got %v", got)
+	}
+	if got := cfg.LargeRepoIndexSize(); got != 0 {
+		t.Errorf("expected negative threshold to disable auto-detection, got %d", got)
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	paths := cfg.LargeRepoPaths()
+	if len(paths) != 2 || paths[0] != filepath.Join(homeDir, "src/mono") || paths[1] != "/opt/big" {
+		t.Errorf("unexpected large repo paths %v", paths)
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	n := 20
+	cfg := &Config{Components: map[string]ComponentConfig{
+		"last_commit": {SubjectLength: &n},
+	}}
+
+	if got := cfg.GetInt("last_commit", "subject_length", 40); got != 20 {
+		t.Errorf("expected configured 20, got %d", got)
+	}
+	if got := cfg.GetInt("repo_info", "subject_length", 40); got != 40 {
+		t.Errorf("expected fallback 40 for unconfigured component, got %d", got)
+	}
+	if got := cfg.GetInt("last_commit", "unknown", 7); got != 7 {
+		t.Errorf("expected fallback 7 for unknown key, got %d", got)
+	}
+}
+
+func TestPricingOverrides_MergesFile(t *testing.T) {
+	if pc, err := (&Config{}).PricingOverrides(); pc != nil || err != nil {
+		t.Errorf("expected nil overrides without [pricing], got %+v (%v)", pc, err)
+	}
+
+	dir := t.TempDir()
+	pricingPath := filepath.Join(dir, "pricing.toml")
+	_ = os.WriteFile(pricingPath, []byte(`
+discount = 0.9
+
+[models."claude-opus-4-6"]
+input = 4.0
+output = 20.0
+cache_write = 5.0
+cache_read = 0.4
+
+[prefixes."claude-next"]
+input = 8.0
+output = 40.0
+`), 0644)
+
+	configPath := filepath.Join(dir, "config.toml")
+	_ = os.WriteFile(configPath, []byte(`
+[layout]
+lines = [{ left = ["repo_info"] }]
+
+[pricing]
+file = "`+pricingPath+`"
+discount = 0.8
+
+[pricing.models."claude-opus-4-6"]
+input = 4.5
+output = 22.5
+
+[pricing.models."claude-opus-4-6".long_context]
+threshold = 200000
+input = 9.0
+output = 33.75
+`), 0644)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	pc, err := cfg.PricingOverrides()
+	if err != nil {
+		t.Fatalf("PricingOverrides failed: %v", err)
+	}
+	if pc.Discount != 0.8 {
+		t.Errorf("expected section discount 0.8 to win, got %v", pc.Discount)
+	}
+	opus := pc.Models["claude-opus-4-6"]
+	if *opus.Input != 4.5 || *opus.Output != 22.5 || opus.CacheRead != nil {
+		t.Errorf("expected section model entry to win, got %+v", opus)
+	}
+	if lc := opus.LongContext; lc == nil || lc.Threshold != 200000 || lc.Input != 9.0 {
+		t.Errorf("expected long-context tier, got %+v", lc)
+	}
+	if got := pc.Prefixes["claude-next"]; got.Input == nil || *got.Input != 8.0 {
+		t.Errorf("expected prefix entry from the pricing file, got %+v", got)
+	}
+
+	cfg.Pricing.File = filepath.Join(dir, "missing.toml")
+	if pc, err := cfg.PricingOverrides(); err == nil || pc.Discount != 0.8 {
+		t.Errorf("expected an error plus the section's own settings, got %+v (%v)", pc, err)
+	}
+}
+
+func TestPricingOverrides_PartialEntryKeepsBuiltinRates(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.toml")
+	_ = os.WriteFile(configPath, []byte(`
+[layout]
+lines = [{ left = ["repo_info"] }]
+
+[pricing.models."claude-opus-4-6"]
+output = 20.0
+
+[pricing.models."claude-sonnet-4-5-20250929"]
+input = 2.5
+
+[pricing.prefixes."claude-nova"]
+input = 2.0
+
+[pricing.default]
+cache_read = 0.25
+`), 0644)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	pc, err := cfg.PricingOverrides()
+	if err != nil {
+		t.Fatalf("PricingOverrides failed: %v", err)
+	}
+	o := pc.Overrides()
+
+	builtin := cost.BuiltinPrice("claude-opus-4-6")
+	want := builtin
+	want.OutputPerMillion = 20.0
+	if got := o.Models["claude-opus-4-6"]; got != want {
+		t.Errorf("expected only the output rate replaced, got %+v, want %+v", got, want)
+	}
+
+	sonnet := o.Models["claude-sonnet-4-5-20250929"]
+	if tier := cost.BuiltinPrice("claude-sonnet-4-5-20250929").LongContext; tier == nil || sonnet.LongContext != tier {
+		t.Errorf("expected the entry to keep the built-in long-context tier, got %+v", sonnet.LongContext)
+	}
+
+	nova := o.Prefixes["claude-nova"]
+	def := cost.BuiltinPrice("")
+	if nova.InputPerMillion != 2.0 || nova.OutputPerMillion != def.OutputPerMillion || nova.CacheReadPerMillion != def.CacheReadPerMillion {
+		t.Errorf("expected an unknown prefix to fill from the default rates, got %+v", nova)
+	}
+
+	if d := o.Default; d == nil || d.CacheReadPerMillion != 0.25 || d.InputPerMillion != def.InputPerMillion {
+		t.Errorf("expected default to keep its other built-in rates, got %+v", d)
+	}
+}
+
+func TestBudget_LoadsLimits(t *testing.T) {
+	empty := &Config{}
+	if got := empty.BudgetLimits(); got != (BudgetLimits{}) {
+		t.Errorf("expected no limits without [budget], got %+v", got)
+	}
+	if got := empty.BudgetWarnAt(); got != DefaultBudgetWarnAt {
+		t.Errorf("expected default warn_at, got %v", got)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	_ = os.WriteFile(configPath, []byte(`
+[layout]
+lines = [{ left = ["budget"] }]
+
+[components.cost_monthly]
+budget_color = true
+
+[budget]
+daily = 20
+monthly = 400
+warn_at = 0.9
+
+[budget.projects."~/src/app"]
+daily = 5
+`), 0644)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.BudgetLimits(); got != (BudgetLimits{Daily: 20, Monthly: 400}) {
+		t.Errorf("unexpected global limits %+v", got)
+	}
+	if got := cfg.BudgetWarnAt(); got != 0.9 {
+		t.Errorf("expected warn_at 0.9, got %v", got)
+	}
+	homeDir, _ := os.UserHomeDir()
+	if got := cfg.ProjectBudget(filepath.Join(homeDir, "src", "app") + "/"); got.Daily != 5 {
+		t.Errorf("expected the project's daily limit, got %+v", got)
+	}
+	if got := cfg.ProjectBudget("/elsewhere"); got != (BudgetLimits{}) {
+		t.Errorf("expected no limits for another project, got %+v", got)
+	}
+	if !cfg.GetBool("cost_monthly", "budget_color", false) {
+		t.Error("expected budget_color to be set for cost_monthly")
+	}
+}
+
+func TestComponentsOfType_FindsCostPeriodInstances(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	_ = os.WriteFile(configPath, []byte(`
+[layout]
+lines = [{ left = ["cost_month", "cost_billing"] }]
+
+[components.cost_monthly]
+window = "month"
+
+[components.cost_month]
+type = "cost_period"
+window = "month"
+
+[components.cost_billing]
+type = "cost_period"
+window = "billing:15"
+label = "CYCLE"
+`), 0644)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	got := cfg.ComponentsOfType("cost_period")
+	if len(got) != 2 || got[0] != "cost_billing" || got[1] != "cost_month" {
+		t.Errorf("expected [cost_billing cost_month], got %v", got)
+	}
+	if got := cfg.GetString("cost_billing", "label", "BILLING"); got != "CYCLE" {
+		t.Errorf("expected label CYCLE, got %q", got)
+	}
+	if got := cfg.GetString("cost_month", "label", "MONTH"); got != "MONTH" {
+		t.Errorf("expected fallback label, got %q", got)
+	}
+}
+
+func TestWindowErrors_ReportsInvalidCostWindows(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	_ = os.WriteFile(configPath, []byte(`
+[layout]
+lines = [{ left = ["cost_weekly", "cost_cycle", "commits"] }]
+
+[components.cost_cycle]
+type = "cost_period"
+window = "billing:40"
+
+[components.cost_weekly]
+window = "fortnight"
+
+[components.cost_monthly]
+window = "month"
+
+[components.commits]
+window = "last-week"
+`), 0644)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("expected an invalid window to load with a warning, got %v", err)
+	}
+	errs := cfg.WindowErrors()
+	if len(errs) != 2 ||
+		!strings.Contains(errs[0].Error(), "components.cost_cycle") ||
+		!strings.Contains(errs[1].Error(), "components.cost_weekly") {
+		t.Errorf("expected errors for cost_cycle and cost_weekly only, got %v", errs)
+	}
+}
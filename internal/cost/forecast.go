@@ -0,0 +1,98 @@
+package cost
+
+import (
+	"fmt"
+	"time"
+)
+
+// forecastHistoryDays is how many whole days before today are compared to
+// weight weekdays against weekends. Four weeks sample both evenly.
+const forecastHistoryDays = 28
+
+// Forecast projects the current calendar month's spend.
+type Forecast struct {
+	MonthToDate float64 `json:"month_to_date"`
+	Projected   float64 `json:"projected"`
+}
+
+// CalculateForecast projects the current calendar month's total spend from
+// its cost so far. Results are cached with a 5 minute TTL, keyed by the
+// current date so the cache resets at midnight.
+func (s *TranscriptScanner) CalculateForecast() Forecast {
+	now := time.Now()
+	cacheKey := fmt.Sprintf("transcript-forecast:%s:%s", costCacheVersion(), now.Format(dayLayout))
+	return cachedJSON(s.cache, cacheKey, func() Forecast {
+		return s.loadIndex().forecast(now)
+	})
+}
+
+// forecast sums the month to date and the weekday and weekend spending of
+// the last forecastHistoryDays whole days, and projects the month from them.
+func (ix *transcriptIndex) forecast(now time.Time) Forecast {
+	today := Day.Start(now)
+	historyStart := today.AddDate(0, 0, -forecastHistoryDays)
+
+	var weekday, weekend float64
+	for _, path := range ix.paths() {
+		ix.Files[path].each(historyStart, func(e usageEntry) {
+			if !e.Time.Before(today) {
+				return
+			}
+			if isWeekend(e.Time) {
+				weekend += e.Cost
+			} else {
+				weekday += e.Cost
+			}
+		})
+	}
+
+	mtd := ix.costSince(Month.Start(now))
+	return Forecast{MonthToDate: mtd, Projected: projectMonth(now, mtd, weekday, weekend)}
+}
+
+// projectMonth extrapolates mtd, the spend of the month up to now, to the
+// end of the month at the month's daily run rate. Days are weighted by how
+// much an average weekday or weekend day cost over the recent history
+// (weekday and weekend are its totals), so a month that has been mostly
+// weekdays is not projected to keep that pace over the weekends. Without
+// history every day weighs the same. The run rate is taken over at least
+// one weighted day, so the first hours of a month do not extrapolate wildly.
+func projectMonth(now time.Time, mtd, weekday, weekend float64) float64 {
+	weekdayWeight, weekendWeight := 1.0, 1.0
+	if weekday+weekend > 0 {
+		// forecastHistoryDays is whole weeks: 5 weekdays and 2 weekend
+		// days in every 7.
+		weeks := float64(forecastHistoryDays / 7)
+		weekdayAvg, weekendAvg := weekday/(5*weeks), weekend/(2*weeks)
+		avg := (weekday + weekend) / forecastHistoryDays
+		weekdayWeight, weekendWeight = weekdayAvg/avg, weekendAvg/avg
+	}
+	weight := func(day time.Time) float64 {
+		if isWeekend(day) {
+			return weekendWeight
+		}
+		return weekdayWeight
+	}
+
+	today := Day.Start(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	done := float64(now.Sub(today)) / float64(tomorrow.Sub(today))
+
+	var elapsed, remaining float64
+	for day := Month.Start(now); day.Before(today); day = day.AddDate(0, 0, 1) {
+		elapsed += weight(day)
+	}
+	elapsed += done * weight(today)
+	remaining += (1 - done) * weight(today)
+	end := Month.Start(now).AddDate(0, 1, 0)
+	for day := tomorrow; day.Before(end); day = day.AddDate(0, 0, 1) {
+		remaining += weight(day)
+	}
+
+	return mtd + mtd/max(elapsed, 1)*remaining
+}
+
+// isWeekend reports whether t falls on a Saturday or Sunday.
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
@@ -0,0 +1,79 @@
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cache"
+)
+
+// forecastNow is midday on Wednesday, 2026-04-15: 14.5 of April's 30 days
+// have passed, 10 of the 14 whole days were weekdays, and 11 of the 15 days
+// left are.
+var forecastNow = time.Date(2026, 4, 15, 12, 0, 0, 0, time.Local)
+
+func TestProjectMonth_NoHistoryUsesFlatRunRate(t *testing.T) {
+	// $10 a day so far, over 15.5 more days.
+	assertCost(t, projectMonth(forecastNow, 145, 0, 0), 300)
+}
+
+func TestProjectMonth_WeightsWeekdays(t *testing.T) {
+	// Weekend-free history: a weekday weighs 28/20 = 1.4 and a weekend day
+	// nothing, so 14.7 weighted days have passed and 16.1 are left.
+	assertCost(t, projectMonth(forecastNow, 147, 20, 0), 147+161)
+
+	// Evenly spread history weighs every day the same.
+	assertCost(t, projectMonth(forecastNow, 145, 20, 8), 300)
+}
+
+func TestProjectMonth_FirstHoursOfMonth(t *testing.T) {
+	now := time.Date(2026, 4, 1, 0, 30, 0, 0, time.Local)
+	// Half an hour in, the rate is taken over a whole day rather than
+	// multiplied by 48.
+	got := projectMonth(now, 1, 0, 0)
+	if got > 31 {
+		t.Errorf("expected at most one day's spend per day, got %f", got)
+	}
+}
+
+func TestTranscriptIndex_Forecast(t *testing.T) {
+	root := t.TempDir()
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 12, 0, 0, 0, time.Local)
+	}
+	// Monday and Tuesday this month, and a Friday in March that only counts
+	// toward the weekday history.
+	appendFile(t, filepath.Join(root, "s1.jsonl"), strings.Join([]string{
+		opusLine("msg_a", 500, at(time.April, 13)),
+		opusLine("msg_b", 500, at(time.April, 14)),
+		opusLine("msg_c", 500, at(time.March, 20)),
+	}, "\n")+"\n")
+
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, forecastNow)
+
+	f := ix.forecast(forecastNow)
+	assertCost(t, f.MonthToDate, 0.035)
+	assertCost(t, f.Projected, 0.035/14.7*30.8)
+}
+
+func TestTranscriptScanner_CalculateForecastIsCached(t *testing.T) {
+	projectsDir := t.TempDir()
+	path := filepath.Join(projectsDir, "-Users-test", "s1.jsonl")
+	_ = os.MkdirAll(filepath.Dir(path), 0755)
+	appendFile(t, path, opusLine("msg_a", 500, time.Now())+"\n")
+
+	scanner := NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+	first := scanner.CalculateForecast()
+	if first.MonthToDate <= 0 || first.Projected < first.MonthToDate {
+		t.Fatalf("expected a projection of at least the month to date, got %+v", first)
+	}
+
+	_ = os.Remove(path)
+	if second := NewTranscriptScanner(projectsDir, scanner.cache).CalculateForecast(); second != first {
+		t.Errorf("expected cached forecast %+v, got %+v", first, second)
+	}
+}
@@ -0,0 +1,504 @@
+package cost
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// indexFoldAge is how old a message must be before its usage is folded into
+// per-day totals. It is longer than the widest rolling window (30 days), so
+// every period the statusline shows is still computed per message.
+const indexFoldAge = 35 * 24 * time.Hour
+
+// indexFormat is bumped when the persisted index layout changes, so indexes
+// written by older binaries are rebuilt rather than misread.
+const indexFormat = 3
+
+// dayLayout formats the local date keying per-day totals.
+const dayLayout = "2006-01-02"
+
+// transcriptIndex is the persistent, incrementally updated view of every
+// transcript under a projects directory. Each scan only parses bytes appended
+// since the previous one.
+type transcriptIndex struct {
+	Files map[string]*fileIndex `json:"files"`
+}
+
+// fileIndex is what has been consumed of one transcript file.
+type fileIndex struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mtime"` // UnixNano
+
+	// Dir is the encoded project directory the file sits under (the first
+	// path element below the projects directory), and CWD the working
+	// directory recorded in its first usage entry. Either identifies the
+	// project; see encodeProjectPath.
+	Dir string `json:"dir,omitempty"`
+	CWD string `json:"cwd,omitempty"`
+
+	// Removed marks a transcript that no longer exists, e.g. because Claude
+	// Code cleaned it up. Its usage is kept so that longer windows and the
+	// report still count it; should the file reappear, it is parsed afresh.
+	Removed bool `json:"removed,omitempty"`
+
+	// Offset is how many bytes have been parsed; it always sits just past a
+	// complete line, so a line still being written is read again next time.
+	Offset int64 `json:"offset"`
+
+	// Messages holds the last entry seen for each message ID. Streaming
+	// duplicates overwrite it, so they collapse even when they straddle two
+	// scans.
+	Messages map[string]usageRecord `json:"messages,omitempty"`
+
+	// Unkeyed holds entries without a message ID, each counted on its own.
+	Unkeyed []usageRecord `json:"unkeyed,omitempty"`
+
+	// Days holds usage folded out of Messages and Unkeyed once older than
+	// indexFoldAge, by local date and then model and branch.
+	Days map[string][]*dayUsage `json:"days,omitempty"`
+}
+
+// dayUsage is the folded usage of one model on one git branch over a day.
+type dayUsage struct {
+	Model  string `json:"m"`
+	Branch string `json:"b,omitempty"`
+	Usage
+}
+
+// usageRecord is the final token usage of one API response and its cost
+// under the pricing the index was built with.
+type usageRecord struct {
+	Model      string  `json:"m"`
+	Branch     string  `json:"b,omitempty"` // git branch of the session, if any
+	Time       int64   `json:"t"`           // UnixMilli
+	Input      int     `json:"i,omitempty"`
+	Output     int     `json:"o,omitempty"`
+	CacheWrite int     `json:"cw,omitempty"`
+	CacheRead  int     `json:"cr,omitempty"`
+	Cost       float64 `json:"c"`
+}
+
+// Usage is the token usage and cost of a number of API responses.
+type Usage struct {
+	Messages   int     `json:"n"`
+	Input      int     `json:"i,omitempty"`
+	Output     int     `json:"o,omitempty"`
+	CacheWrite int     `json:"cw,omitempty"`
+	CacheRead  int     `json:"cr,omitempty"`
+	Cost       float64 `json:"c"`
+}
+
+// Add adds o to u.
+func (u *Usage) Add(o Usage) {
+	u.Messages += o.Messages
+	u.Input += o.Input
+	u.Output += o.Output
+	u.CacheWrite += o.CacheWrite
+	u.CacheRead += o.CacheRead
+	u.Cost += o.Cost
+}
+
+// usage returns the record as the Usage of a single response.
+func (r usageRecord) usage() Usage {
+	return Usage{
+		Messages:   1,
+		Input:      r.Input,
+		Output:     r.Output,
+		CacheWrite: r.CacheWrite,
+		CacheRead:  r.CacheRead,
+		Cost:       r.Cost,
+	}
+}
+
+func newTranscriptIndex() *transcriptIndex {
+	return &transcriptIndex{Files: make(map[string]*fileIndex)}
+}
+
+// scanWorkers bounds how many transcripts are parsed at once. Parsing is
+// mostly JSON decoding, so workers beyond the available CPUs only contend.
+var scanWorkers = min(runtime.GOMAXPROCS(0), 8)
+
+// scanJob is one transcript with bytes that still need parsing.
+type scanJob struct {
+	path  string
+	size  int64
+	mtime int64 // UnixNano
+	fi    *fileIndex
+	err   error
+}
+
+// update brings the index in line with the .jsonl files under root, skipping
+// tool-results directories and files last modified before modifiedSince: new
+// files are parsed, grown ones from their last offset, and unchanged ones not
+// at all. A file that shrank was rewritten and is parsed again from the
+// start; a file that disappeared (or was skipped) is marked Removed but keeps
+// its usage, unless it had none, in which case it is dropped. Finally, usage
+// older than indexFoldAge at now is folded into per-day totals. It reports
+// whether anything changed.
+//
+// Files are parsed concurrently by up to scanWorkers goroutines, each into
+// its own fileIndex, and merged back in walk order once all are done.
+func (ix *transcriptIndex) update(root string, modifiedSince, now time.Time) bool {
+	jobs, seen := ix.pending(root, modifiedSince)
+	parseAll(jobs, scanWorkers)
+
+	changed := false
+	for _, job := range jobs {
+		if job.err != nil {
+			continue
+		}
+		job.fi.Size, job.fi.ModTime = job.size, job.mtime
+		ix.Files[job.path] = job.fi
+		changed = true
+	}
+	for path, fi := range ix.Files {
+		if seen[path] || fi.Removed {
+			continue
+		}
+		if fi.empty() {
+			delete(ix.Files, path)
+		} else {
+			fi.Removed = true
+		}
+		changed = true
+	}
+	for _, fi := range ix.Files {
+		if fi.fold(now.Add(-indexFoldAge)) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// pending walks root and returns a job for every transcript that changed
+// since it was indexed, in lexical order, plus the set of all transcripts
+// found.
+func (ix *transcriptIndex) pending(root string, modifiedSince time.Time) ([]*scanJob, map[string]bool) {
+	var jobs []*scanJob
+	seen := make(map[string]bool)
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && d.Name() == "tool-results" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || filepath.Ext(path) != ".jsonl" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(modifiedSince) {
+			return nil
+		}
+		seen[path] = true
+
+		size, mtime := info.Size(), info.ModTime().UnixNano()
+		fi := ix.Files[path]
+		if fi != nil && fi.Size == size && fi.ModTime == mtime {
+			return nil
+		}
+		if fi == nil || fi.Removed || size < fi.Offset {
+			fi = &fileIndex{Dir: projectDirName(root, path)}
+		}
+		jobs = append(jobs, &scanJob{path: path, size: size, mtime: mtime, fi: fi})
+		return nil
+	})
+	return jobs, seen
+}
+
+// parseAll parses every job's new bytes into its fileIndex using at most
+// workers goroutines. Jobs share no state, so no locking is needed.
+func parseAll(jobs []*scanJob, workers int) {
+	queue := make(chan *scanJob)
+	var wg sync.WaitGroup
+	for range min(workers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				job.err = job.fi.readFrom(job.path)
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// costSince sums the cost of all usage after cutoff. Folded days count when
+// they start at or after cutoff.
+func (ix *transcriptIndex) costSince(cutoff time.Time) float64 {
+	return ix.sumSince(cutoff, func(*fileIndex) bool { return true })
+}
+
+// projectCostSince is costSince restricted to the transcripts of the project
+// at dir.
+func (ix *transcriptIndex) projectCostSince(dir string, cutoff time.Time) float64 {
+	return ix.sumSince(cutoff, func(fi *fileIndex) bool { return fi.inProject(dir) })
+}
+
+// usageByModel sums the usage after cutoff per model ID. Models with no
+// usage in the window are left out.
+func (ix *transcriptIndex) usageByModel(cutoff time.Time) map[string]Usage {
+	totals := make(map[string]Usage)
+	for _, path := range ix.paths() {
+		ix.Files[path].each(cutoff, func(e usageEntry) {
+			t := totals[e.Model]
+			t.Add(e.Usage)
+			totals[e.Model] = t
+		})
+	}
+	return totals
+}
+
+// sumSince sums the cost after cutoff of the files keep accepts. Files are
+// summed in path order so the total does not depend on map iteration order.
+func (ix *transcriptIndex) sumSince(cutoff time.Time, keep func(*fileIndex) bool) float64 {
+	var total float64
+	for _, path := range ix.paths() {
+		if fi := ix.Files[path]; keep(fi) {
+			total += fi.costSince(cutoff)
+		}
+	}
+	return total
+}
+
+// paths returns the indexed file paths in sorted order.
+func (ix *transcriptIndex) paths() []string {
+	paths := make([]string, 0, len(ix.Files))
+	for path := range ix.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// project returns the directory of the project the transcript belongs to:
+// its recorded working directory or, for a transcript without one, the
+// encoded directory name, which cannot be decoded unambiguously.
+func (fi *fileIndex) project() string {
+	if fi.CWD != "" {
+		return fi.CWD
+	}
+	return fi.Dir
+}
+
+// inProject reports whether the transcript belongs to the project at dir:
+// it recorded dir or a directory below it as its working directory, so a
+// nested project also counts toward its parent. Only a transcript without a
+// recorded directory falls back to being stored under dir's encoded name,
+// which other paths can share.
+func (fi *fileIndex) inProject(dir string) bool {
+	dir = filepath.Clean(dir)
+	if fi.CWD == "" {
+		return fi.Dir == encodeProjectPath(dir)
+	}
+	return fi.CWD == dir || strings.HasPrefix(fi.CWD, dir+string(filepath.Separator))
+}
+
+// encodeProjectPath returns the directory name Claude Code stores a
+// project's transcripts under: the path with every character other than an
+// ASCII letter or digit replaced by "-", e.g. "/Users/me/my.app" becomes
+// "-Users-me-my-app".
+func encodeProjectPath(dir string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return '-'
+	}, dir)
+}
+
+// projectDirName returns the first element of path below root, or "" for a
+// file directly in root.
+func projectDirName(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return ""
+	}
+	dir, _, found := strings.Cut(filepath.ToSlash(rel), "/")
+	if !found {
+		return ""
+	}
+	return dir
+}
+
+// readFrom parses the lines of path after fi.Offset and advances it. A final
+// line without a newline is only consumed once it is valid JSON, since until
+// then it may still be being written.
+func (fi *fileIndex) readFrom(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Seek(fi.Offset, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 && json.Valid(line) {
+				fi.add(line)
+				fi.Offset += int64(len(line))
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fi.Offset += int64(len(line))
+		fi.add(bytes.TrimSpace(line))
+	}
+}
+
+// add records one transcript line if it is an assistant entry with usage.
+func (fi *fileIndex) add(line []byte) {
+	if len(line) == 0 {
+		return
+	}
+	entry, ok := parseTranscriptEntry(line)
+	if !ok {
+		return
+	}
+	if fi.CWD == "" {
+		fi.CWD = entry.CWD
+	}
+	r := usageRecord{
+		Model:      entry.Model,
+		Branch:     entry.Branch,
+		Time:       entry.Timestamp.UnixMilli(),
+		Input:      entry.InputTokens,
+		Output:     entry.OutputTokens,
+		CacheWrite: entry.CacheWriteTokens,
+		CacheRead:  entry.CacheReadTokens,
+		Cost: CalculateEntryCost(
+			entry.InputTokens, entry.OutputTokens,
+			entry.CacheWriteTokens, entry.CacheReadTokens,
+			entry.Model,
+		),
+	}
+	if entry.MessageID == "" {
+		fi.Unkeyed = append(fi.Unkeyed, r)
+		return
+	}
+	if fi.Messages == nil {
+		fi.Messages = make(map[string]usageRecord)
+	}
+	// Last write wins — later entries for the same ID have final token counts.
+	fi.Messages[entry.MessageID] = r
+}
+
+// empty reports whether the file has no usage at all.
+func (fi *fileIndex) empty() bool {
+	return len(fi.Messages) == 0 && len(fi.Unkeyed) == 0 && len(fi.Days) == 0
+}
+
+// fold moves usage from before cutoff into per-day totals and reports
+// whether there was any.
+func (fi *fileIndex) fold(cutoff time.Time) bool {
+	limit := cutoff.UnixMilli()
+	folded := false
+	for id, r := range fi.Messages {
+		if r.Time < limit {
+			fi.addDay(r)
+			delete(fi.Messages, id)
+			folded = true
+		}
+	}
+	kept := fi.Unkeyed[:0]
+	for _, r := range fi.Unkeyed {
+		if r.Time < limit {
+			fi.addDay(r)
+			folded = true
+			continue
+		}
+		kept = append(kept, r)
+	}
+	fi.Unkeyed = kept
+	return folded
+}
+
+func (fi *fileIndex) addDay(r usageRecord) {
+	day := time.UnixMilli(r.Time).Format(dayLayout)
+	if fi.Days == nil {
+		fi.Days = make(map[string][]*dayUsage)
+	}
+	for _, d := range fi.Days[day] {
+		if d.Model == r.Model && d.Branch == r.Branch {
+			d.Add(r.usage())
+			return
+		}
+	}
+	fi.Days[day] = append(fi.Days[day], &dayUsage{Model: r.Model, Branch: r.Branch, Usage: r.usage()})
+}
+
+// costSince sums the file's usage after cutoff.
+func (fi *fileIndex) costSince(cutoff time.Time) float64 {
+	var total float64
+	fi.each(cutoff, func(e usageEntry) { total += e.Cost })
+	return total
+}
+
+// usageEntry is the usage of one record, or of one model and branch in a
+// folded day, as visited by each.
+type usageEntry struct {
+	Time   time.Time // of the record, or the start of the folded day
+	Model  string
+	Branch string
+	Usage
+}
+
+// each calls fn with every record after cutoff, and every model and branch
+// of each folded day that starts at or after cutoff. Messages and days are
+// visited in key order, so floating-point sums over them are reproducible.
+func (fi *fileIndex) each(cutoff time.Time, fn func(e usageEntry)) {
+	limit := cutoff.UnixMilli()
+	record := func(r usageRecord) {
+		if r.Time > limit {
+			fn(usageEntry{Time: time.UnixMilli(r.Time), Model: r.Model, Branch: r.Branch, Usage: r.usage()})
+		}
+	}
+	for _, id := range sortedKeys(fi.Messages) {
+		record(fi.Messages[id])
+	}
+	for _, r := range fi.Unkeyed {
+		record(r)
+	}
+	for _, day := range sortedKeys(fi.Days) {
+		start, err := time.ParseInLocation(dayLayout, day, time.Local)
+		if err != nil || start.Before(cutoff) {
+			continue
+		}
+		for _, d := range fi.Days[day] {
+			fn(usageEntry{Time: start, Model: d.Model, Branch: d.Branch, Usage: d.Usage})
+		}
+	}
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
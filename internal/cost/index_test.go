@@ -0,0 +1,283 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cache"
+)
+
+// opusLine returns an assistant transcript line for claude-opus-4-5 with the
+// given message ID, output tokens, and timestamp. With 1000 input tokens it
+// costs (1000*5 + output*25) / 1M.
+func opusLine(id string, output int, ts time.Time) string {
+	return fmt.Sprintf(`{"type":"assistant","message":{"id":"%s","model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000,"output_tokens":%d,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"%s"}`,
+		id, output, ts.UTC().Format(time.RFC3339Nano))
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertCost(t *testing.T, got, want float64) {
+	t.Helper()
+	if got < want-0.0001 || got > want+0.0001 {
+		t.Errorf("expected %f, got %f", want, got)
+	}
+}
+
+func TestTranscriptIndex_DeduplicatesAcrossIncrementalScans(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "s1.jsonl")
+	now := time.Now()
+	ts := now.Add(-time.Hour)
+
+	appendFile(t, path, opusLine("msg_a", 2, ts)+"\n")
+	ix := newTranscriptIndex()
+	if !ix.update(root, time.Time{}, now) {
+		t.Fatal("expected the first update to report a change")
+	}
+	assertCost(t, ix.costSince(now.Add(-24*time.Hour)), (1000*5+2*25)/1e6)
+
+	// The final streaming entry for msg_a lands in the next scan.
+	appendFile(t, path, opusLine("msg_a", 500, ts)+"\n"+opusLine("msg_b", 0, ts)+"\n")
+	if !ix.update(root, time.Time{}, now) {
+		t.Fatal("expected the appended lines to be picked up")
+	}
+	assertCost(t, ix.costSince(now.Add(-24*time.Hour)), 0.0175+0.005)
+
+	info, _ := os.Stat(path)
+	if fi := ix.Files[path]; fi.Offset != info.Size() {
+		t.Errorf("expected offset %d at end of file, got %d", info.Size(), fi.Offset)
+	}
+	if ix.update(root, time.Time{}, now) {
+		t.Error("expected no change for an untouched file")
+	}
+}
+
+func TestTranscriptIndex_WaitsForPartialLine(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "s1.jsonl")
+	now := time.Now()
+	line := opusLine("msg_a", 500, now.Add(-time.Hour))
+
+	appendFile(t, path, line[:40])
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, now)
+	if fi := ix.Files[path]; fi.Offset != 0 {
+		t.Errorf("expected a partial line not to be consumed, offset %d", fi.Offset)
+	}
+
+	appendFile(t, path, line[40:]+"\n")
+	ix.update(root, time.Time{}, now)
+	assertCost(t, ix.costSince(now.Add(-24*time.Hour)), 0.0175)
+}
+
+func TestTranscriptIndex_RewrittenAndDeletedFiles(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "s1.jsonl")
+	now := time.Now()
+	ts := now.Add(-time.Hour)
+
+	appendFile(t, path, opusLine("msg_a", 500, ts)+"\n"+opusLine("msg_b", 500, ts)+"\n")
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, now)
+
+	// A shorter file was rewritten, not appended to.
+	_ = os.WriteFile(path, []byte(opusLine("msg_c", 0, ts)+"\n"), 0644)
+	ix.update(root, time.Time{}, now)
+	assertCost(t, ix.costSince(now.Add(-24*time.Hour)), 0.005)
+
+	// A deleted transcript keeps counting.
+	_ = os.Remove(path)
+	if !ix.update(root, time.Time{}, now) {
+		t.Error("expected the deletion to be recorded")
+	}
+	if fi := ix.Files[path]; fi == nil || !fi.Removed {
+		t.Fatalf("expected deleted transcript to be kept as removed, got %+v", fi)
+	}
+	assertCost(t, ix.costSince(now.Add(-24*time.Hour)), 0.005)
+
+	// Recreated, it is parsed from the start rather than counted twice.
+	appendFile(t, path, opusLine("msg_c", 0, ts)+"\n"+opusLine("msg_d", 0, ts)+"\n")
+	ix.update(root, time.Time{}, now)
+	assertCost(t, ix.costSince(now.Add(-24*time.Hour)), 0.01)
+
+	// A transcript without usage is dropped outright.
+	empty := filepath.Join(root, "s2.jsonl")
+	appendFile(t, empty, `{"type":"user","message":{"role":"user","content":"hi"}}`+"\n")
+	ix.update(root, time.Time{}, now)
+	_ = os.Remove(empty)
+	ix.update(root, time.Time{}, now)
+	if _, ok := ix.Files[empty]; ok {
+		t.Error("expected a deleted transcript without usage to be dropped")
+	}
+}
+
+func TestTranscriptIndex_KeepsFoldedDaysOfDeletedFiles(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "s1.jsonl")
+	now := time.Now()
+	old := now.Add(-60 * 24 * time.Hour)
+
+	appendFile(t, path, opusLine("msg_old", 500, old)+"\n"+opusLine("msg_new", 500, now.Add(-time.Hour))+"\n")
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, now)
+	cutoff := now.Add(-90 * 24 * time.Hour)
+	assertCost(t, ix.costSince(cutoff), 0.035)
+
+	// As when Claude Code cleans up transcripts after cleanupPeriodDays.
+	_ = os.Remove(path)
+	ix.update(root, time.Time{}, now)
+	assertCost(t, ix.costSince(cutoff), 0.035)
+	if got := ix.usageByModel(cutoff)["claude-opus-4-5-20251101"]; got.Messages != 2 {
+		t.Errorf("expected both messages to survive the deletion, got %+v", got)
+	}
+}
+
+func TestTranscriptIndex_FoldsOldUsageIntoDays(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "s1.jsonl")
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+
+	appendFile(t, path, opusLine("msg_old", 500, old)+"\n"+opusLine("msg_new", 500, now.Add(-time.Hour))+"\n")
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, now)
+
+	fi := ix.Files[path]
+	if _, ok := fi.Messages["msg_old"]; ok || len(fi.Messages) != 1 {
+		t.Errorf("expected only the recent message to stay per-message, got %v", fi.Messages)
+	}
+	days := fi.Days[old.Format(dayLayout)]
+	if len(days) != 1 || days[0].Model != "claude-opus-4-5-20251101" || days[0].Messages != 1 || days[0].Output != 500 {
+		t.Fatalf("expected the old message in its day's totals, got %+v", days)
+	}
+
+	startOfOldDay := time.Date(old.Year(), old.Month(), old.Day(), 0, 0, 0, 0, time.Local)
+	assertCost(t, ix.costSince(startOfOldDay), 0.035)
+	assertCost(t, ix.costSince(now.Add(-30*24*time.Hour)), 0.0175)
+}
+
+func TestTranscriptScanner_PersistsIndex(t *testing.T) {
+	projectsDir := t.TempDir()
+	path := filepath.Join(projectsDir, "s1.jsonl")
+	ts := time.Now().Add(-time.Hour)
+	appendFile(t, path, opusLine("msg_a", 500, ts)+"\n")
+
+	cacheDir := t.TempDir()
+	assertCost(t, NewTranscriptScanner(projectsDir, cache.New(cacheDir)).CalculatePeriod(24*time.Hour), 0.0175)
+
+	// Same size and mtime but different usage: a new scanner must trust the
+	// persisted index rather than re-parse the file.
+	info, _ := os.Stat(path)
+	_ = os.WriteFile(path, []byte(opusLine("msg_a", 900, ts)+"\n"), 0644)
+	_ = os.Chtimes(path, info.ModTime(), info.ModTime())
+
+	assertCost(t, NewTranscriptScanner(projectsDir, cache.New(cacheDir)).CalculatePeriod(48*time.Hour), 0.0175)
+}
+
+// withCWD adds a cwd field to a transcript line.
+func withCWD(line, cwd string) string {
+	return strings.Replace(line, `{`, `{"cwd":"`+cwd+`",`, 1)
+}
+
+func TestEncodeProjectPath(t *testing.T) {
+	tests := map[string]string{
+		"/Users/me/app":        "-Users-me-app",
+		"/Users/me/my.app":     "-Users-me-my-app",
+		"/home/me/.config/x_y": "-home-me--config-x-y",
+		"/srv/café":            "-srv-caf-",
+	}
+	for dir, want := range tests {
+		if got := encodeProjectPath(dir); got != want {
+			t.Errorf("encodeProjectPath(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestTranscriptIndex_ProjectCosts(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	ts := now.Add(-time.Hour)
+	write := func(dir, name, data string) {
+		_ = os.MkdirAll(filepath.Join(root, dir), 0755)
+		appendFile(t, filepath.Join(root, dir, name), data+"\n")
+	}
+
+	// Stored under the app's encoded name, with and without a recorded cwd.
+	write("-work-app", "a.jsonl", withCWD(opusLine("msg_a", 500, ts), "/work/app"))
+	write("-work-app", "b.jsonl", opusLine("msg_b", 0, ts))
+	// Started in a subdirectory, so stored under that directory's name.
+	write("-work-app-web", "c.jsonl", withCWD(opusLine("msg_c", 0, ts), "/work/app/web"))
+	// A different project whose encoded name collides with nothing above.
+	write("-work-api", "d.jsonl", withCWD(opusLine("msg_d", 500, ts), "/work/api"))
+	// A project whose encoded name collides with the subdirectory's.
+	write("-work-app-web", "e.jsonl", withCWD(opusLine("msg_e", 500, ts), "/work/app-web"))
+
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, now)
+	cutoff := now.Add(-24 * time.Hour)
+
+	assertCost(t, ix.projectCostSince("/work/app", cutoff), 0.0175+0.005+0.005)
+	assertCost(t, ix.projectCostSince("/work/app/", cutoff), 0.0175+0.005+0.005)
+	assertCost(t, ix.projectCostSince("/work/api", cutoff), 0.0175)
+	assertCost(t, ix.projectCostSince("/work/ap", cutoff), 0)
+	// The nested directory counts toward both itself and its parent.
+	assertCost(t, ix.projectCostSince("/work/app/web", cutoff), 0.005)
+	// A recorded cwd decides, not the encoded name it shares.
+	assertCost(t, ix.projectCostSince("/work/app-web", cutoff), 0.0175)
+
+	byProject := make(map[string]float64)
+	for _, row := range ix.report(ReportQuery{Since: cutoff, Until: now, Period: Month, GroupBy: ByProject}) {
+		byProject[row.Group] += row.Cost
+	}
+	if len(byProject) != 5 {
+		t.Fatalf("expected 5 projects, got %v", byProject)
+	}
+	assertCost(t, byProject["/work/app"], 0.0175)
+	assertCost(t, byProject["-work-app"], 0.005)
+	assertCost(t, byProject["/work/app/web"], 0.005)
+	assertCost(t, byProject["/work/api"], 0.0175)
+	assertCost(t, byProject["/work/app-web"], 0.0175)
+}
+
+func TestTranscriptIndex_UsageByModel(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "s1.jsonl")
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+	haiku := strings.Replace(opusLine("msg_h", 100, now.Add(-time.Hour)), "claude-opus-4-5-20251101", "claude-haiku-4-5-20251001", 1)
+
+	appendFile(t, path, opusLine("msg_old", 500, old)+"\n"+
+		opusLine("msg_a", 500, now.Add(-time.Hour))+"\n"+
+		opusLine("msg_a", 600, now.Add(-time.Hour))+"\n"+
+		haiku+"\n")
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, now)
+
+	recent := ix.usageByModel(now.Add(-24 * time.Hour))
+	opus := recent["claude-opus-4-5-20251101"]
+	if len(recent) != 2 || opus.Messages != 1 || opus.Input != 1000 || opus.Output != 600 {
+		t.Errorf("expected one deduplicated Opus message and Haiku, got %+v", recent)
+	}
+	assertCost(t, opus.Cost, 0.02)
+	assertCost(t, recent["claude-haiku-4-5-20251001"].Cost, 0.0015)
+
+	startOfOldDay := time.Date(old.Year(), old.Month(), old.Day(), 0, 0, 0, 0, time.Local)
+	if all := ix.usageByModel(startOfOldDay)["claude-opus-4-5-20251101"]; all.Messages != 2 || all.Output != 1100 {
+		t.Errorf("expected the folded day to count toward Opus, got %+v", all)
+	}
+}
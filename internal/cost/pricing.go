@@ -1,60 +1,248 @@
 package cost
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
 // Pricing represents per-million-token rates for a model.
 type Pricing struct {
 	InputPerMillion      float64
 	OutputPerMillion     float64
 	CacheWritePerMillion float64
 	CacheReadPerMillion  float64
+
+	// LongContext, when set, replaces every rate for requests whose input
+	// exceeds its threshold, as with 1M-context billing.
+	LongContext *Tier
+}
+
+// Tier holds the per-million-token rates charged once a request's total
+// input -- uncached, cache write, and cache read tokens -- exceeds Threshold.
+type Tier struct {
+	Threshold            int
+	InputPerMillion      float64
+	OutputPerMillion     float64
+	CacheWritePerMillion float64
+	CacheReadPerMillion  float64
 }
 
+// sonnet4LongContext is the premium Sonnet 4 and 4.5 charge above 200K input
+// tokens with the 1M context window.
+var sonnet4LongContext = &Tier{200_000, 6.0, 22.50, 7.50, 0.60}
+
 var pricingTable = map[string]Pricing{
-	"claude-fable-5":             {10.0, 50.0, 12.5, 1.0},
-	"claude-mythos-5":            {10.0, 50.0, 12.5, 1.0},
-	"claude-mythos-preview":      {10.0, 50.0, 12.5, 1.0},
-	"claude-opus-4-5-20251101":   {5.0, 25.0, 6.25, 0.50},
-	"claude-opus-4-6":            {5.0, 25.0, 6.25, 0.50},
-	"claude-opus-4-8":            {5.0, 25.0, 6.25, 0.50},
-	"claude-sonnet-5":            {3.0, 15.0, 3.75, 0.30},
-	"claude-sonnet-4-5-20251101": {3.0, 15.0, 3.75, 0.30},
-	"claude-sonnet-4-5-20250929": {3.0, 15.0, 3.75, 0.30},
-	"claude-sonnet-4-20250514":   {3.0, 15.0, 3.75, 0.30},
-	"claude-haiku-4-5-20251101":  {1.0, 5.0, 1.25, 0.10},
-	"claude-haiku-4-5-20251001":  {1.0, 5.0, 1.25, 0.10},
-}
-
-var defaultPricing = Pricing{3.0, 15.0, 3.75, 0.30}
+	"claude-fable-5":             {10.0, 50.0, 12.5, 1.0, nil},
+	"claude-mythos-5":            {10.0, 50.0, 12.5, 1.0, nil},
+	"claude-mythos-preview":      {10.0, 50.0, 12.5, 1.0, nil},
+	"claude-opus-4-5-20251101":   {5.0, 25.0, 6.25, 0.50, nil},
+	"claude-opus-4-6":            {5.0, 25.0, 6.25, 0.50, nil},
+	"claude-opus-4-8":            {5.0, 25.0, 6.25, 0.50, nil},
+	"claude-sonnet-5":            {3.0, 15.0, 3.75, 0.30, nil},
+	"claude-sonnet-4-5-20251101": {3.0, 15.0, 3.75, 0.30, sonnet4LongContext},
+	"claude-sonnet-4-5-20250929": {3.0, 15.0, 3.75, 0.30, sonnet4LongContext},
+	"claude-sonnet-4-20250514":   {3.0, 15.0, 3.75, 0.30, sonnet4LongContext},
+	"claude-haiku-4-5-20251101":  {1.0, 5.0, 1.25, 0.10, nil},
+	"claude-haiku-4-5-20251001":  {1.0, 5.0, 1.25, 0.10, nil},
+}
+
+var defaultPricing = Pricing{3.0, 15.0, 3.75, 0.30, nil}
 
 var prefixPricing = []struct {
 	prefix  string
 	pricing Pricing
 }{
-	{"claude-fable", Pricing{10.0, 50.0, 12.5, 1.0}},
-	{"claude-mythos", Pricing{10.0, 50.0, 12.5, 1.0}},
-	{"claude-opus", Pricing{5.0, 25.0, 6.25, 0.50}},
-	{"claude-sonnet", Pricing{3.0, 15.0, 3.75, 0.30}},
-	{"claude-haiku", Pricing{1.0, 5.0, 1.25, 0.10}},
+	{"claude-fable", Pricing{10.0, 50.0, 12.5, 1.0, nil}},
+	{"claude-mythos", Pricing{10.0, 50.0, 12.5, 1.0, nil}},
+	{"claude-opus", Pricing{5.0, 25.0, 6.25, 0.50, nil}},
+	{"claude-sonnet-5", Pricing{3.0, 15.0, 3.75, 0.30, nil}},
+	{"claude-sonnet", Pricing{3.0, 15.0, 3.75, 0.30, sonnet4LongContext}},
+	{"claude-haiku", Pricing{1.0, 5.0, 1.25, 0.10, nil}},
+}
+
+// Overrides replaces or extends the built-in pricing, e.g. for models newer
+// than this binary or negotiated enterprise rates.
+type Overrides struct {
+	Models   map[string]Pricing // exact model IDs; checked before anything else
+	Prefixes map[string]Pricing // model ID prefixes; the longest match wins
+	Default  *Pricing           // replaces the Sonnet-tier fallback for unknown models
+	Discount float64            // multiplier applied to every rate; 0 means none
+}
+
+// isZero reports whether o changes no price.
+func (o *Overrides) isZero() bool {
+	return len(o.Models) == 0 && len(o.Prefixes) == 0 && o.Default == nil &&
+		(o.Discount == 0 || o.Discount == 1)
+}
+
+var overrides atomic.Pointer[Overrides]
+
+// SetOverrides installs o over the built-in pricing for every later
+// ModelPrice call.
+func SetOverrides(o Overrides) {
+	if o.isZero() {
+		overrides.Store(nil)
+		return
+	}
+	overrides.Store(&o)
+}
+
+// PricingFingerprint returns a short hash of the installed overrides, or ""
+// when there are none. Cached costs are keyed by it so that editing the
+// pricing invalidates them.
+func PricingFingerprint() string {
+	o := overrides.Load()
+	if o == nil {
+		return ""
+	}
+	data, _ := json.Marshal(o) // map keys are sorted, so this is stable
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
 }
 
 // ModelPrice returns per-million-token pricing for a model identifier.
-// Checks exact matches, then prefix matches, then falls back to Sonnet-tier default.
+// Checks override exact and prefix matches, then the built-in exact and
+// prefix matches, then falls back to the override default or the Sonnet-tier
+// default. The override discount applies to whichever rate is found.
 func ModelPrice(model string) Pricing {
-	if p, ok := pricingTable[model]; ok {
+	o := overrides.Load()
+	if o == nil {
+		if p, ok := builtinPrice(model); ok {
+			return p
+		}
+		return defaultPricing
+	}
+
+	p, ok := o.Models[model]
+	if !ok {
+		p, ok = longestPrefix(o.Prefixes, model)
+	}
+	if !ok {
+		p, ok = builtinPrice(model)
+	}
+	if !ok {
+		p = defaultPricing
+		if o.Default != nil {
+			p = *o.Default
+		}
+	}
+	if o.Discount > 0 {
+		p = p.scaled(o.Discount)
+	}
+	return p
+}
+
+// BuiltinPrice returns the compiled-in pricing for a model identifier,
+// ignoring any overrides: its exact or prefix match, else the Sonnet-tier
+// default.
+func BuiltinPrice(model string) Pricing {
+	if p, ok := builtinPrice(model); ok {
 		return p
 	}
+	return defaultPricing
+}
+
+// builtinPrice looks model up in the compiled-in exact and prefix tables.
+func builtinPrice(model string) (Pricing, bool) {
+	if p, ok := pricingTable[model]; ok {
+		return p, true
+	}
 	for _, pp := range prefixPricing {
-		if len(model) >= len(pp.prefix) && model[:len(pp.prefix)] == pp.prefix {
-			return pp.pricing
+		if strings.HasPrefix(model, pp.prefix) {
+			return pp.pricing, true
 		}
 	}
-	return defaultPricing
+	return Pricing{}, false
+}
+
+// longestPrefix returns the entry of prefixes with the longest key that
+// model starts with.
+func longestPrefix(prefixes map[string]Pricing, model string) (Pricing, bool) {
+	keys := make([]string, 0, len(prefixes))
+	for k := range prefixes {
+		if strings.HasPrefix(model, k) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return Pricing{}, false
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	return prefixes[keys[0]], true
+}
+
+// scaled returns p, including its long-context tier, with every rate
+// multiplied by f.
+func (p Pricing) scaled(f float64) Pricing {
+	scaled := Pricing{
+		InputPerMillion:      p.InputPerMillion * f,
+		OutputPerMillion:     p.OutputPerMillion * f,
+		CacheWritePerMillion: p.CacheWritePerMillion * f,
+		CacheReadPerMillion:  p.CacheReadPerMillion * f,
+	}
+	if t := p.LongContext; t != nil {
+		scaled.LongContext = &Tier{
+			Threshold:            t.Threshold,
+			InputPerMillion:      t.InputPerMillion * f,
+			OutputPerMillion:     t.OutputPerMillion * f,
+			CacheWritePerMillion: t.CacheWritePerMillion * f,
+			CacheReadPerMillion:  t.CacheReadPerMillion * f,
+		}
+	}
+	return scaled
+}
+
+// ForInput returns the rates that apply to a request with the given total
+// input tokens: the long-context tier's above its threshold, else p's own.
+func (p Pricing) ForInput(tokens int) Pricing {
+	t := p.LongContext
+	if t == nil || tokens <= t.Threshold {
+		return p
+	}
+	return Pricing{
+		InputPerMillion:      t.InputPerMillion,
+		OutputPerMillion:     t.OutputPerMillion,
+		CacheWritePerMillion: t.CacheWritePerMillion,
+		CacheReadPerMillion:  t.CacheReadPerMillion,
+	}
 }
 
 // CalculateEntryCost computes the USD cost for a single transcript entry.
+// The entry's total input tokens pick between standard and long-context rates.
 func CalculateEntryCost(inputTokens, outputTokens, cacheWriteTokens, cacheReadTokens int, model string) float64 {
-	p := ModelPrice(model)
+	p := ModelPrice(model).ForInput(inputTokens + cacheWriteTokens + cacheReadTokens)
 	return (float64(inputTokens)*p.InputPerMillion +
 		float64(outputTokens)*p.OutputPerMillion +
 		float64(cacheWriteTokens)*p.CacheWritePerMillion +
 		float64(cacheReadTokens)*p.CacheReadPerMillion) / 1_000_000
 }
+
+// modelFamilies are the display names of the model families, checked in
+// order against a lowercased model ID.
+var modelFamilies = []struct {
+	match string
+	name  string
+}{
+	{"fable", "Fable"},
+	{"mythos", "Mythos"},
+	{"opus", "Opus"},
+	{"sonnet", "Sonnet"},
+	{"haiku", "Haiku"},
+}
+
+// ModelFamily returns the family name of a model ID, e.g. "Opus" for
+// "claude-opus-4-6" or a Bedrock ID like "us.anthropic.claude-opus-4-6-v1",
+// or the ID itself when it belongs to no known family.
+func ModelFamily(model string) string {
+	lower := strings.ToLower(model)
+	for _, f := range modelFamilies {
+		if strings.Contains(lower, f.match) {
+			return f.name
+		}
+	}
+	return model
+}
@@ -86,3 +86,124 @@ func TestCalculateEntryCost_ZeroTokens(t *testing.T) {
 		t.Errorf("expected 0.0 for zero tokens, got %f", cost)
 	}
 }
+
+func TestModelPrice_Overrides(t *testing.T) {
+	t.Cleanup(func() { SetOverrides(Overrides{}) })
+	negotiated := Pricing{4.0, 20.0, 5.0, 0.40, nil}
+	fallback := Pricing{2.0, 10.0, 2.5, 0.20, nil}
+	SetOverrides(Overrides{
+		Models: map[string]Pricing{"claude-sonnet-5": {1, 2, 3, 4, nil}},
+		Prefixes: map[string]Pricing{
+			"claude-":      {9, 9, 9, 9, nil},
+			"claude-opus-": negotiated,
+		},
+		Default: &fallback,
+	})
+
+	tests := []struct {
+		model string
+		want  Pricing
+	}{
+		{"claude-sonnet-5", Pricing{1, 2, 3, 4, nil}},           // exact override
+		{"claude-opus-4-6", negotiated},                         // longest prefix beats built-in exact
+		{"claude-haiku-4-5-20251001", Pricing{9, 9, 9, 9, nil}}, // short prefix still beats built-ins
+		{"gpt-5", fallback},                                     // unknown model uses override default
+	}
+	for _, tt := range tests {
+		if got := ModelPrice(tt.model); got != tt.want {
+			t.Errorf("ModelPrice(%q) = %+v, want %+v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestModelPrice_Discount(t *testing.T) {
+	t.Cleanup(func() { SetOverrides(Overrides{}) })
+	SetOverrides(Overrides{Discount: 0.5})
+
+	p := ModelPrice("claude-opus-4-6")
+	if p != (Pricing{2.5, 12.5, 3.125, 0.25, nil}) {
+		t.Errorf("expected Opus rates halved, got %+v", p)
+	}
+}
+
+func TestPricingFingerprint(t *testing.T) {
+	t.Cleanup(func() { SetOverrides(Overrides{}) })
+
+	SetOverrides(Overrides{Discount: 1})
+	if fp := PricingFingerprint(); fp != "" {
+		t.Errorf("expected no fingerprint for a no-op override, got %q", fp)
+	}
+
+	SetOverrides(Overrides{Discount: 0.8})
+	first := PricingFingerprint()
+	SetOverrides(Overrides{Discount: 0.9})
+	if first == "" || first == PricingFingerprint() {
+		t.Errorf("expected distinct non-empty fingerprints, got %q and %q", first, PricingFingerprint())
+	}
+}
+
+func TestCalculateEntryCost_LongContextTier(t *testing.T) {
+	// 150K input + 60K cache read = 210K total input, over the 200K threshold:
+	// (150000*6 + 1000*22.50 + 60000*0.60) / 1M = 0.9585
+	cost := CalculateEntryCost(150_000, 1000, 0, 60_000, "claude-sonnet-4-5-20250929")
+	if expected := 0.9585; cost < expected-0.0001 || cost > expected+0.0001 {
+		t.Errorf("expected long-context cost %f, got %f", expected, cost)
+	}
+
+	// Exactly at the threshold the standard rates still apply:
+	// (200000*3 + 1000*15) / 1M = 0.615
+	cost = CalculateEntryCost(200_000, 1000, 0, 0, "claude-sonnet-4-5-20250929")
+	if expected := 0.615; cost < expected-0.0001 || cost > expected+0.0001 {
+		t.Errorf("expected standard cost %f at the threshold, got %f", expected, cost)
+	}
+}
+
+func TestModelPrice_SonnetPrefixKeepsLongContextTier(t *testing.T) {
+	if tier := ModelPrice("claude-sonnet-4-5-20260101").LongContext; tier != sonnet4LongContext {
+		t.Errorf("expected an unlisted Sonnet 4 ID to get the long-context tier, got %+v", tier)
+	}
+	if tier := ModelPrice("claude-sonnet-5-20260101").LongContext; tier != nil {
+		t.Errorf("expected no long-context tier for Sonnet 5, got %+v", tier)
+	}
+}
+
+func TestPricing_ForInput(t *testing.T) {
+	p := Pricing{1, 2, 3, 4, &Tier{Threshold: 100, InputPerMillion: 10, OutputPerMillion: 20}}
+	if got := p.ForInput(100); got != p {
+		t.Errorf("expected standard rates at the threshold, got %+v", got)
+	}
+	if got := p.ForInput(101); got != (Pricing{10, 20, 0, 0, nil}) {
+		t.Errorf("expected tier rates above the threshold, got %+v", got)
+	}
+	if got := (Pricing{1, 2, 3, 4, nil}).ForInput(1_000_000); got.InputPerMillion != 1 {
+		t.Errorf("expected flat pricing without a tier, got %+v", got)
+	}
+}
+
+func TestModelPrice_DiscountScalesTier(t *testing.T) {
+	t.Cleanup(func() { SetOverrides(Overrides{}) })
+	SetOverrides(Overrides{Discount: 0.5})
+
+	tier := ModelPrice("claude-sonnet-4-20250514").LongContext
+	if tier == nil || tier.Threshold != 200_000 || tier.InputPerMillion != 3.0 {
+		t.Errorf("expected halved long-context tier, got %+v", tier)
+	}
+	if sonnet4LongContext.InputPerMillion != 6.0 {
+		t.Error("discount must not modify the built-in tier")
+	}
+}
+
+func TestModelFamily(t *testing.T) {
+	tests := map[string]string{
+		"claude-opus-4-6": "Opus",
+		"us.anthropic.claude-sonnet-4-5-20250929-v1": "Sonnet",
+		"claude-haiku-4-5-20251001":                  "Haiku",
+		"claude-mythos-preview":                      "Mythos",
+		"gpt-5":                                      "gpt-5",
+	}
+	for model, want := range tests {
+		if got := ModelFamily(model); got != want {
+			t.Errorf("ModelFamily(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
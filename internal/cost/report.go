@@ -0,0 +1,128 @@
+package cost
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Period is the span of time one report row covers.
+type Period string
+
+const (
+	Day   Period = "day"
+	Week  Period = "week" // ISO week, starting Monday
+	Month Period = "month"
+)
+
+// Start returns the start of the period containing t, in t's location.
+func (p Period) Start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch p {
+	case Week:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// Label formats the period starting at start: "2026-10-17" for a day,
+// "2026-W42" for an ISO week, and "2026-10" for a month.
+func (p Period) Label(start time.Time) string {
+	switch p {
+	case Week:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case Month:
+		return start.Format("2006-01")
+	default:
+		return start.Format(dayLayout)
+	}
+}
+
+// Grouping splits each report period's usage further.
+type Grouping string
+
+const (
+	ByNone    Grouping = ""
+	ByProject Grouping = "project"
+	ByModel   Grouping = "model"
+	ByBranch  Grouping = "branch"
+)
+
+// ReportQuery selects what a usage report covers: usage after Since and
+// before Until, summed per Period and, unless GroupBy is ByNone, per group.
+type ReportQuery struct {
+	Since   time.Time
+	Until   time.Time
+	Period  Period
+	GroupBy Grouping
+}
+
+// ReportRow is the usage of one period, or of one group within it. Group is
+// the project directory (a transcript's recorded cwd, else its encoded
+// directory name, e.g. "-Users-me-app"), model ID, or git branch ("" for
+// sessions outside a branch), and empty when the report is not grouped.
+type ReportRow struct {
+	Start time.Time
+	Group string
+	Usage
+}
+
+// Report sums transcript usage as q describes, ordered by period and then
+// group. Periods and groups without usage are left out. It is not cached.
+func (s *TranscriptScanner) Report(q ReportQuery) []ReportRow {
+	return s.loadIndex().report(q)
+}
+
+func (ix *transcriptIndex) report(q ReportQuery) []ReportRow {
+	type key struct {
+		start int64
+		group string
+	}
+	totals := make(map[key]*ReportRow)
+	for _, path := range ix.paths() {
+		fi := ix.Files[path]
+		fi.each(q.Since, func(e usageEntry) {
+			if !e.Time.Before(q.Until) {
+				return
+			}
+			start := q.Period.Start(e.Time.In(time.Local))
+			k := key{start.Unix(), groupOf(q.GroupBy, fi, e)}
+			row := totals[k]
+			if row == nil {
+				row = &ReportRow{Start: start, Group: k.group}
+				totals[k] = row
+			}
+			row.Add(e.Usage)
+		})
+	}
+
+	rows := make([]ReportRow, 0, len(totals))
+	for _, row := range totals {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].Start.Equal(rows[j].Start) {
+			return rows[i].Start.Before(rows[j].Start)
+		}
+		return rows[i].Group < rows[j].Group
+	})
+	return rows
+}
+
+// groupOf returns the group e falls into under g.
+func groupOf(g Grouping, fi *fileIndex, e usageEntry) string {
+	switch g {
+	case ByProject:
+		return fi.project()
+	case ByModel:
+		return e.Model
+	case ByBranch:
+		return e.Branch
+	default:
+		return ""
+	}
+}
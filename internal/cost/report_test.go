@@ -0,0 +1,92 @@
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriod_StartAndLabel(t *testing.T) {
+	// Thursday, 2026-01-01 is in ISO week 2026-W01, which starts on Monday,
+	// 2025-12-29.
+	at := time.Date(2026, 1, 1, 15, 4, 5, 0, time.Local)
+	tests := []struct {
+		period    Period
+		wantStart time.Time
+		wantLabel string
+	}{
+		{Day, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local), "2026-01-01"},
+		{Week, time.Date(2025, 12, 29, 0, 0, 0, 0, time.Local), "2026-W01"},
+		{Month, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local), "2026-01"},
+	}
+	for _, tt := range tests {
+		start := tt.period.Start(at)
+		if !start.Equal(tt.wantStart) {
+			t.Errorf("%s.Start = %v, want %v", tt.period, start, tt.wantStart)
+		}
+		if label := tt.period.Label(start); label != tt.wantLabel {
+			t.Errorf("%s.Label = %q, want %q", tt.period, label, tt.wantLabel)
+		}
+	}
+	if start := Week.Start(time.Date(2026, 1, 4, 12, 0, 0, 0, time.Local)); start.Day() != 29 {
+		t.Errorf("expected Sunday to belong to the week starting Monday the 29th, got %v", start)
+	}
+}
+
+func TestTranscriptIndex_Report(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	today := Day.Start(now)
+	yesterday := today.AddDate(0, 0, -1).Add(12 * time.Hour)
+	old := today.AddDate(0, 0, -40).Add(12 * time.Hour)
+	onBranch := func(line, branch string) string {
+		return strings.Replace(line, `{`, `{"gitBranch":"`+branch+`",`, 1)
+	}
+
+	_ = os.MkdirAll(filepath.Join(root, "-work-app"), 0755)
+	appendFile(t, filepath.Join(root, "-work-app", "s1.jsonl"), strings.Join([]string{
+		withCWD(onBranch(opusLine("msg_a", 500, yesterday), "main"), "/work/app"),
+		withCWD(onBranch(opusLine("msg_b", 0, yesterday), "feature"), "/work/app"),
+		withCWD(onBranch(opusLine("msg_c", 500, old), "main"), "/work/app"),
+		withCWD(opusLine("msg_d", 0, now.Add(-time.Minute)), "/work/app"),
+	}, "\n")+"\n")
+
+	ix := newTranscriptIndex()
+	ix.update(root, time.Time{}, now)
+
+	rows := ix.report(ReportQuery{
+		Since:   today.AddDate(0, 0, -60),
+		Until:   today,
+		Period:  Day,
+		GroupBy: ByBranch,
+	})
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows before today, got %+v", rows)
+	}
+	if !rows[0].Start.Equal(Day.Start(old)) || rows[0].Group != "main" || rows[0].Output != 500 {
+		t.Errorf("expected the folded day first, got %+v", rows[0])
+	}
+	if rows[1].Group != "feature" || rows[2].Group != "main" || !rows[1].Start.Equal(Day.Start(yesterday)) {
+		t.Errorf("expected yesterday's branches in order, got %+v", rows[1:])
+	}
+
+	monthly := ix.report(ReportQuery{
+		Since:   today.AddDate(0, 0, -2),
+		Until:   today.AddDate(0, 0, 1),
+		Period:  Month,
+		GroupBy: ByProject,
+	})
+	var total Usage
+	for _, row := range monthly {
+		if row.Group != "/work/app" {
+			t.Errorf("expected every row in /work/app, got %q", row.Group)
+		}
+		total.Add(row.Usage)
+	}
+	if total.Messages != 3 {
+		t.Errorf("expected 3 messages since two days ago, got %+v", monthly)
+	}
+	assertCost(t, total.Cost, 0.0175+0.005+0.005)
+}
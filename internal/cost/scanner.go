@@ -1,8 +1,10 @@
 package cost
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/h2ik/claude-statusline/internal/cache"
@@ -10,15 +12,36 @@ import (
 
 const transcriptCacheTTL = 5 * time.Minute
 
+// transcriptIndexTTL bounds how long an untouched transcript index is
+// trusted. Every scan that finds new data rewrites it, so in practice it only
+// expires after a month without Claude Code use.
+const transcriptIndexTTL = 30 * 24 * time.Hour
+
 // cacheVersion is bumped when the cost calculation logic changes, which
 // automatically invalidates stale cached values from older binaries.
-const cacheVersion = "v2"
+const cacheVersion = "v3"
+
+// costCacheVersion is cacheVersion qualified by the pricing fingerprint, so
+// editing the pricing overrides invalidates cached totals just like a new
+// binary does.
+func costCacheVersion() string {
+	if fp := PricingFingerprint(); fp != "" {
+		return cacheVersion + "-" + fp
+	}
+	return cacheVersion
+}
 
 // TranscriptScanner computes period costs by scanning Claude Code's native
-// JSONL transcript files. Results are cached for 5 minutes.
+// JSONL transcript files. Results are cached for 5 minutes. Scans go through
+// a transcript index persisted in the cache, so each one only parses what
+// was appended to the transcripts since the last.
 type TranscriptScanner struct {
 	projectsDir string
 	cache       *cache.Cache
+
+	mu           sync.Mutex
+	index        *transcriptIndex
+	indexVersion string
 }
 
 // NewTranscriptScanner creates a scanner reading from the given projects directory.
@@ -29,7 +52,23 @@ func NewTranscriptScanner(projectsDir string, c *cache.Cache) *TranscriptScanner
 // CalculatePeriod returns the total USD cost from all transcripts within the
 // given duration. Results are cached per-duration with a 5 minute TTL.
 func (s *TranscriptScanner) CalculatePeriod(duration time.Duration) float64 {
-	cacheKey := fmt.Sprintf("transcript-cost:%s:%s", cacheVersion, duration.String())
+	return s.CalculateWindow(Window{Rolling: duration})
+}
+
+// CalculateToday returns the total USD cost from all transcripts since
+// midnight local time today. Results are cached with a 5 minute TTL,
+// keyed by the current date so the cache resets at midnight.
+func (s *TranscriptScanner) CalculateToday() float64 {
+	return s.CalculateWindow(Window{Calendar: Day})
+}
+
+// CalculateWindow returns the total USD cost from all transcripts within w.
+// Results are cached per window with a 5 minute TTL; calendar and billing
+// windows are keyed by their start date, so the cache resets when a new one
+// begins.
+func (s *TranscriptScanner) CalculateWindow(w Window) float64 {
+	now := time.Now()
+	cacheKey := fmt.Sprintf("transcript-cost:%s:%s", costCacheVersion(), w.cacheKey(now))
 
 	if data, err := s.cache.Get(cacheKey, transcriptCacheTTL); err == nil {
 		if val, err := strconv.ParseFloat(string(data), 64); err == nil {
@@ -37,26 +76,101 @@ func (s *TranscriptScanner) CalculatePeriod(duration time.Duration) float64 {
 		}
 	}
 
-	total := ScanTranscripts(s.projectsDir, duration)
+	total := s.loadIndex().costSince(w.Start(now))
 	_ = s.cache.Set(cacheKey, []byte(strconv.FormatFloat(total, 'f', 6, 64)), transcriptCacheTTL)
 	return total
 }
 
-// CalculateToday returns the total USD cost from all transcripts since
-// midnight local time today. Results are cached with a 5 minute TTL,
+// ProjectCost is one project's cost over the same windows as the global
+// totals: since local midnight, and the rolling 7 and 30 days.
+type ProjectCost struct {
+	Today float64 `json:"today"`
+	Week  float64 `json:"week"`
+	Month float64 `json:"month"`
+}
+
+// CalculateProject returns the cost of the transcripts belonging to the
+// project at projectDir. Results are cached per project with a 5 minute TTL,
 // keyed by the current date so the cache resets at midnight.
-func (s *TranscriptScanner) CalculateToday() float64 {
+func (s *TranscriptScanner) CalculateProject(projectDir string) ProjectCost {
 	now := time.Now()
 	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	cacheKey := fmt.Sprintf("transcript-cost:%s:today:%s", cacheVersion, midnight.Format("2006-01-02"))
+	cacheKey := fmt.Sprintf("transcript-cost:%s:project:%s:%s", costCacheVersion(), midnight.Format("2006-01-02"), projectDir)
 
-	if data, err := s.cache.Get(cacheKey, transcriptCacheTTL); err == nil {
-		if val, err := strconv.ParseFloat(string(data), 64); err == nil {
-			return val
+	return cachedJSON(s.cache, cacheKey, func() ProjectCost {
+		ix := s.loadIndex()
+		return ProjectCost{
+			Today: ix.projectCostSince(projectDir, midnight),
+			Week:  ix.projectCostSince(projectDir, now.Add(-7*24*time.Hour)),
+			Month: ix.projectCostSince(projectDir, now.Add(-30*24*time.Hour)),
+		}
+	})
+}
+
+// CalculateProjectWindow returns the cost within w of the transcripts
+// belonging to the project at projectDir. Results are cached per project and
+// window with a 5 minute TTL, like CalculateWindow.
+func (s *TranscriptScanner) CalculateProjectWindow(projectDir string, w Window) float64 {
+	now := time.Now()
+	cacheKey := fmt.Sprintf("transcript-cost:%s:project-window:%s:%s", costCacheVersion(), w.cacheKey(now), projectDir)
+	return cachedJSON(s.cache, cacheKey, func() float64 {
+		return s.loadIndex().projectCostSince(projectDir, w.Start(now))
+	})
+}
+
+// CalculateWindowByModel returns the usage from all transcripts within w,
+// keyed by model ID. Results are cached per window with a 5 minute TTL, like
+// CalculateWindow.
+func (s *TranscriptScanner) CalculateWindowByModel(w Window) map[string]Usage {
+	now := time.Now()
+	cacheKey := fmt.Sprintf("transcript-models:%s:%s", costCacheVersion(), w.cacheKey(now))
+	return cachedJSON(s.cache, cacheKey, func() map[string]Usage {
+		return s.loadIndex().usageByModel(w.Start(now))
+	})
+}
+
+// cachedJSON returns the value cached under key, or computes it and caches
+// it as JSON with the transcript cache TTL.
+func cachedJSON[T any](c *cache.Cache, key string, compute func() T) T {
+	var v T
+	if data, err := c.Get(key, transcriptCacheTTL); err == nil {
+		if err := json.Unmarshal(data, &v); err == nil {
+			return v
 		}
 	}
+	v = compute()
+	if data, err := json.Marshal(v); err == nil {
+		_ = c.Set(key, data, transcriptCacheTTL)
+	}
+	return v
+}
 
-	total := ScanTranscriptsSince(s.projectsDir, midnight)
-	_ = s.cache.Set(cacheKey, []byte(strconv.FormatFloat(total, 'f', 6, 64)), transcriptCacheTTL)
-	return total
+// loadIndex returns the transcript index brought up to date with the
+// projects directory. It is read from the cache and updated once per process
+// (and again if the pricing changes), then saved back when anything changed.
+// An unreadable cached index is rebuilt from scratch.
+func (s *TranscriptScanner) loadIndex() *transcriptIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version := costCacheVersion()
+	if s.index != nil && s.indexVersion == version {
+		return s.index
+	}
+
+	key := fmt.Sprintf("transcript-index:%d:%s:%s", indexFormat, version, s.projectsDir)
+	ix := newTranscriptIndex()
+	if data, err := s.cache.Get(key, transcriptIndexTTL); err == nil {
+		if err := json.Unmarshal(data, ix); err != nil || ix.Files == nil {
+			ix = newTranscriptIndex()
+		}
+	}
+	if ix.update(s.projectsDir, time.Time{}, time.Now()) {
+		if data, err := json.Marshal(ix); err == nil {
+			_ = s.cache.Set(key, data, transcriptIndexTTL)
+		}
+	}
+
+	s.index, s.indexVersion = ix, version
+	return ix
 }
@@ -84,3 +84,52 @@ func TestTranscriptScanner_EmptyProjectsDir(t *testing.T) {
 		t.Errorf("expected 0.0, got %f", total)
 	}
 }
+
+func TestTranscriptScanner_PricingChangeInvalidatesCache(t *testing.T) {
+	t.Cleanup(func() { SetOverrides(Overrides{}) })
+	projectsDir := t.TempDir()
+	projDir := filepath.Join(projectsDir, "-Users-test")
+	_ = os.MkdirAll(projDir, 0755)
+	_ = os.WriteFile(filepath.Join(projDir, "s1.jsonl"), []byte(
+		fmt.Sprintf(`{"type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000,"output_tokens":500,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"%s"}`, recentTimestamp())+"\n",
+	), 0644)
+
+	scanner := NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+	full := scanner.CalculatePeriod(30 * 24 * time.Hour)
+
+	SetOverrides(Overrides{Discount: 0.5})
+	discounted := scanner.CalculatePeriod(30 * 24 * time.Hour)
+
+	if discounted < full/2-0.0001 || discounted > full/2+0.0001 {
+		t.Errorf("expected discounted total %f after a pricing change, got %f", full/2, discounted)
+	}
+}
+
+func TestTranscriptScanner_CalculateProject(t *testing.T) {
+	projectsDir := t.TempDir()
+	line := `{"cwd":"%s","type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000,"output_tokens":500,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"%s"}` + "\n"
+	for dir, offset := range map[string]time.Duration{
+		"/Users/test/app": -10 * 24 * time.Hour,
+		"/Users/test/api": -1 * time.Minute,
+	} {
+		projDir := filepath.Join(projectsDir, encodeProjectPath(dir))
+		_ = os.MkdirAll(projDir, 0755)
+		_ = os.WriteFile(filepath.Join(projDir, "s1.jsonl"), []byte(
+			fmt.Sprintf(line, dir, time.Now().Add(offset).UTC().Format(time.RFC3339Nano)),
+		), 0644)
+	}
+
+	c := cache.New(t.TempDir())
+	scanner := NewTranscriptScanner(projectsDir, c)
+	pc := scanner.CalculateProject("/Users/test/app")
+	if pc.Today != 0 || pc.Week != 0 || pc.Month < 0.0174 || pc.Month > 0.0176 {
+		t.Errorf("expected only the 30-day window to include the app's usage, got %+v", pc)
+	}
+
+	// With the transcripts gone, only the cached result can still be found.
+	_ = os.RemoveAll(projectsDir)
+	cached := NewTranscriptScanner(projectsDir, c).CalculateProject("/Users/test/app")
+	if cached != pc {
+		t.Errorf("expected cached %+v, got %+v", pc, cached)
+	}
+}
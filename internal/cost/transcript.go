@@ -1,10 +1,7 @@
 package cost
 
 import (
-	"bufio"
 	"encoding/json"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 )
@@ -18,6 +15,8 @@ type transcriptEntry struct {
 	CacheWriteTokens int
 	CacheReadTokens  int
 	Timestamp        time.Time
+	CWD              string
+	Branch           string
 }
 
 // rawTranscriptLine is the minimal JSON structure we unmarshal.
@@ -35,6 +34,8 @@ type rawTranscriptLine struct {
 		} `json:"usage"`
 	} `json:"message"`
 	Timestamp string `json:"timestamp"`
+	CWD       string `json:"cwd"`
+	GitBranch string `json:"gitBranch"`
 }
 
 // parseTranscriptEntry parses a single JSONL line and returns a transcriptEntry
@@ -62,6 +63,8 @@ func parseTranscriptEntry(line []byte) (transcriptEntry, bool) {
 		CacheWriteTokens: raw.Message.Usage.CacheCreationInputTokens,
 		CacheReadTokens:  raw.Message.Usage.CacheReadInputTokens,
 		Timestamp:        ts,
+		CWD:              raw.CWD,
+		Branch:           raw.GitBranch,
 	}, true
 }
 
@@ -74,65 +77,11 @@ func parseTranscriptEntry(line []byte) (transcriptEntry, bool) {
 // the last entry for each ID, which has the final token counts. Entries
 // without a message ID are counted individually.
 func scanFile(path string, cutoff time.Time) float64 {
-	f, err := os.Open(path)
-	if err != nil {
+	fi := &fileIndex{}
+	if err := fi.readFrom(path); err != nil {
 		return 0.0
 	}
-	defer func() { _ = f.Close() }()
-
-	// Track the last entry per message ID so streaming duplicates collapse.
-	type entryData struct {
-		inputTokens      int
-		outputTokens     int
-		cacheWriteTokens int
-		cacheReadTokens  int
-		model            string
-	}
-	deduped := make(map[string]entryData)
-	var noIDTotal float64
-
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-		entry, ok := parseTranscriptEntry(line)
-		if !ok {
-			continue
-		}
-		if !entry.Timestamp.After(cutoff) {
-			continue
-		}
-		if entry.MessageID == "" {
-			noIDTotal += CalculateEntryCost(
-				entry.InputTokens, entry.OutputTokens,
-				entry.CacheWriteTokens, entry.CacheReadTokens,
-				entry.Model,
-			)
-			continue
-		}
-		// Last write wins — later entries for the same ID have final token counts.
-		deduped[entry.MessageID] = entryData{
-			inputTokens:      entry.InputTokens,
-			outputTokens:     entry.OutputTokens,
-			cacheWriteTokens: entry.CacheWriteTokens,
-			cacheReadTokens:  entry.CacheReadTokens,
-			model:            entry.Model,
-		}
-	}
-
-	var total float64
-	for _, e := range deduped {
-		total += CalculateEntryCost(
-			e.inputTokens, e.outputTokens,
-			e.cacheWriteTokens, e.cacheReadTokens,
-			e.model,
-		)
-	}
-	return total + noIDTotal
+	return fi.costSince(cutoff)
 }
 
 // ScanTranscripts walks the root directory (typically ~/.claude/projects/)
@@ -145,25 +94,10 @@ func ScanTranscripts(root string, duration time.Duration) float64 {
 // ScanTranscriptsSince walks the root directory recursively, summing costs
 // from all .jsonl files whose entries have timestamps after the given cutoff.
 // Skips tool-results directories. Uses mtime pre-filtering to skip stale files.
+// Every other file is parsed in full; the TranscriptScanner keeps a persistent
+// index instead so that repeated scans only read what was appended.
 func ScanTranscriptsSince(root string, cutoff time.Time) float64 {
-	var total float64
-
-	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-		if info.IsDir() && info.Name() == "tool-results" {
-			return filepath.SkipDir
-		}
-		if info.IsDir() || filepath.Ext(path) != ".jsonl" {
-			return nil
-		}
-		if info.ModTime().Before(cutoff) {
-			return nil
-		}
-		total += scanFile(path, cutoff)
-		return nil
-	})
-
-	return total
+	ix := newTranscriptIndex()
+	ix.update(root, cutoff, time.Now())
+	return ix.costSince(cutoff)
 }
@@ -276,3 +276,82 @@ func TestScanTranscripts_SkipsToolResultsDir(t *testing.T) {
 		t.Errorf("expected %f, got %f", expected, total)
 	}
 }
+
+// writeSyntheticTree creates projects*sessions transcripts under root, each
+// with lines assistant entries, a user entry between each, and a streaming
+// duplicate for every message.
+func writeSyntheticTree(tb testing.TB, root string, projects, sessions, lines int) {
+	tb.Helper()
+	ts := recentTS(-1 * time.Hour)
+	for p := range projects {
+		dir := filepath.Join(root, fmt.Sprintf("-Users-test-project-%d", p))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			tb.Fatal(err)
+		}
+		for s := range sessions {
+			var b strings.Builder
+			for i := range lines {
+				fmt.Fprintf(&b, `{"type":"user","message":{"role":"user","content":"turn %d"},"timestamp":"%s"}`+"\n", i, ts)
+				for _, out := range []int{1, syntheticOutputTokens(p, s, i)} {
+					fmt.Fprintf(&b, `{"type":"assistant","message":{"id":"msg_%d_%d_%d","model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000,"output_tokens":%d,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"%s"}`+"\n", p, s, i, out, ts)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("session-%d.jsonl", s)), []byte(b.String()), 0644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+}
+
+// syntheticOutputTokens varies the final output of each message in a
+// synthetic tree, so every message costs something different and the order
+// of summing them shows in the total's last bits.
+func syntheticOutputTokens(project, session, line int) int {
+	return 500 + 37*project + 11*session + 3*line
+}
+
+func TestScanTranscripts_ParallelMatchesSequential(t *testing.T) {
+	root := t.TempDir()
+	writeSyntheticTree(t, root, 4, 25, 20)
+
+	var want float64
+	for p := range 4 {
+		for s := range 25 {
+			for i := range 20 {
+				want += CalculateEntryCost(1000, syntheticOutputTokens(p, s, i), 0, 0, "claude-opus-4-5-20251101")
+			}
+		}
+	}
+
+	defer func(n int) { scanWorkers = n }(scanWorkers)
+	scanWorkers = 1
+	sequential := ScanTranscripts(root, 24*time.Hour)
+	assertCost(t, sequential, want)
+
+	// Repeat so that differing schedules and map orders get a chance to show.
+	scanWorkers = 8
+	for range 10 {
+		if parallel := ScanTranscripts(root, 24*time.Hour); parallel != sequential {
+			t.Fatalf("expected parallel total %v to equal sequential %v", parallel, sequential)
+		}
+	}
+}
+
+// BenchmarkScanTranscriptsSince compares parsing a synthetic tree of 2,000
+// transcripts with a single worker against pools of 4 and 8. The pool only
+// helps with as many CPUs; compare with -cpu 1,8.
+func BenchmarkScanTranscriptsSince(b *testing.B) {
+	root := b.TempDir()
+	writeSyntheticTree(b, root, 40, 50, 20)
+	cutoff := time.Now().Add(-24 * time.Hour)
+
+	defer func(n int) { scanWorkers = n }(scanWorkers)
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			scanWorkers = workers
+			for b.Loop() {
+				ScanTranscriptsSince(root, cutoff)
+			}
+		})
+	}
+}
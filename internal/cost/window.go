@@ -0,0 +1,135 @@
+package cost
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is the span of time ending now that a cost total covers. Exactly
+// one of its fields is set: a rolling duration, a calendar period, or the
+// day of the month a billing cycle starts on.
+type Window struct {
+	Rolling    time.Duration
+	Calendar   Period
+	BillingDay int // 1-31; clamped to the last day of shorter months
+}
+
+// ParseWindow parses a window spec:
+//
+//	"today"      calendar day since local midnight (also "day")
+//	"week"       calendar ISO week, starting Monday
+//	"month"      calendar month
+//	"billing:15" billing cycle starting on the 15th of each month
+//	"7d", "48h"  rolling duration: whole days, or any time.ParseDuration value
+func ParseWindow(spec string) (Window, error) {
+	switch spec {
+	case "today", "day":
+		return Window{Calendar: Day}, nil
+	case "week":
+		return Window{Calendar: Week}, nil
+	case "month":
+		return Window{Calendar: Month}, nil
+	}
+
+	if day, ok := strings.CutPrefix(spec, "billing:"); ok {
+		n, err := strconv.Atoi(day)
+		if err != nil || n < 1 || n > 31 {
+			return Window{}, fmt.Errorf("invalid billing day %q: want 1-31", day)
+		}
+		return Window{BillingDay: n}, nil
+	}
+
+	var d time.Duration
+	if days, ok := strings.CutSuffix(spec, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid window %q", spec)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(spec); err != nil {
+			return Window{}, fmt.Errorf("invalid window %q", spec)
+		}
+	}
+	if d <= 0 {
+		return Window{}, fmt.Errorf("invalid window %q: duration must be positive", spec)
+	}
+	return Window{Rolling: d}, nil
+}
+
+// Start returns the start of the window ending at now.
+func (w Window) Start(now time.Time) time.Time {
+	switch {
+	case w.Rolling > 0:
+		return now.Add(-w.Rolling)
+	case w.BillingDay > 0:
+		start := billingDate(now.Year(), now.Month(), w.BillingDay, now.Location())
+		if now.Before(start) {
+			start = billingDate(now.Year(), now.Month()-1, w.BillingDay, now.Location())
+		}
+		return start
+	default:
+		return w.Calendar.Start(now)
+	}
+}
+
+// billingDate returns midnight on the given day of month, or on the last day
+// of the month when it is shorter.
+func billingDate(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// Label is the short uppercase label components show for the window:
+// "TODAY", "WEEK", "MONTH", "BILLING", "7DAY", or "48H".
+func (w Window) Label() string {
+	switch {
+	case w.Rolling > 0:
+		switch {
+		case w.Rolling%(24*time.Hour) == 0:
+			return fmt.Sprintf("%dDAY", w.Rolling/(24*time.Hour))
+		case w.Rolling%time.Hour == 0:
+			return fmt.Sprintf("%dH", w.Rolling/time.Hour)
+		default:
+			return fmt.Sprintf("%dM", w.Rolling/time.Minute)
+		}
+	case w.BillingDay > 0:
+		return "BILLING"
+	case w.Calendar == Week:
+		return "WEEK"
+	case w.Calendar == Month:
+		return "MONTH"
+	default:
+		return "TODAY"
+	}
+}
+
+// String returns the window's spec, as accepted by ParseWindow.
+func (w Window) String() string {
+	switch {
+	case w.Rolling > 0:
+		return w.Rolling.String()
+	case w.BillingDay > 0:
+		return fmt.Sprintf("billing:%d", w.BillingDay)
+	case w.Calendar == "" || w.Calendar == Day:
+		return "today"
+	default:
+		return string(w.Calendar)
+	}
+}
+
+// cacheKey identifies the window's result in the cache. Rolling windows are
+// keyed by their duration; the others by their start date, so the cache
+// resets when a new day, week, month, or billing cycle begins.
+func (w Window) cacheKey(now time.Time) string {
+	if w.Rolling > 0 {
+		return w.Rolling.String()
+	}
+	return w.String() + ":" + w.Start(now).Format(dayLayout)
+}
@@ -0,0 +1,124 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cache"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		spec  string
+		want  Window
+		label string
+	}{
+		{"today", Window{Calendar: Day}, "TODAY"},
+		{"day", Window{Calendar: Day}, "TODAY"},
+		{"week", Window{Calendar: Week}, "WEEK"},
+		{"month", Window{Calendar: Month}, "MONTH"},
+		{"billing:15", Window{BillingDay: 15}, "BILLING"},
+		{"7d", Window{Rolling: 7 * 24 * time.Hour}, "7DAY"},
+		{"48h", Window{Rolling: 48 * time.Hour}, "2DAY"},
+		{"36h", Window{Rolling: 36 * time.Hour}, "36H"},
+		{"90m", Window{Rolling: 90 * time.Minute}, "90M"},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.spec)
+		if err != nil {
+			t.Errorf("ParseWindow(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseWindow(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+		if label := got.Label(); label != tt.label {
+			t.Errorf("ParseWindow(%q).Label() = %q, want %q", tt.spec, label, tt.label)
+		}
+		if again, err := ParseWindow(got.String()); err != nil || again != got {
+			t.Errorf("ParseWindow(%q) does not round-trip through %q", tt.spec, got.String())
+		}
+	}
+
+	for _, spec := range []string{"", "fortnight", "billing:0", "billing:32", "-1h", "0d", "xd"} {
+		if _, err := ParseWindow(spec); err == nil {
+			t.Errorf("expected ParseWindow(%q) to fail", spec)
+		}
+	}
+}
+
+func TestWindow_Start(t *testing.T) {
+	at := time.Date(2026, 3, 10, 15, 4, 5, 0, time.Local)
+	tests := []struct {
+		window Window
+		want   time.Time
+	}{
+		{Window{Rolling: 48 * time.Hour}, at.Add(-48 * time.Hour)},
+		{Window{Calendar: Day}, time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)},
+		{Window{Calendar: Week}, time.Date(2026, 3, 9, 0, 0, 0, 0, time.Local)},
+		{Window{Calendar: Month}, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		// The cycle starting on the 15th began last month...
+		{Window{BillingDay: 15}, time.Date(2026, 2, 15, 0, 0, 0, 0, time.Local)},
+		// ...and one starting on the 10th began today.
+		{Window{BillingDay: 10}, time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)},
+		// February has no 31st, so that cycle started on the 28th.
+		{Window{BillingDay: 31}, time.Date(2026, 2, 28, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		if got := tt.window.Start(at); !got.Equal(tt.want) {
+			t.Errorf("%s.Start = %v, want %v", tt.window, got, tt.want)
+		}
+	}
+
+	jan := time.Date(2026, 1, 5, 12, 0, 0, 0, time.Local)
+	if got := (Window{BillingDay: 20}).Start(jan); !got.Equal(time.Date(2025, 12, 20, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("expected the cycle to start in the previous year, got %v", got)
+	}
+}
+
+func TestWindow_CacheKey(t *testing.T) {
+	at := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		window Window
+		want   string
+	}{
+		// Rolling and today keys match those used before windows existed.
+		{Window{Rolling: 30 * 24 * time.Hour}, "720h0m0s"},
+		{Window{Calendar: Day}, "today:2026-10-17"},
+		{Window{Calendar: Week}, "week:2026-10-12"},
+		{Window{Calendar: Month}, "month:2026-10-01"},
+		{Window{BillingDay: 15}, "billing:15:2026-10-15"},
+	}
+	for _, tt := range tests {
+		if got := tt.window.cacheKey(at); got != tt.want {
+			t.Errorf("%s.cacheKey = %q, want %q", tt.window, got, tt.want)
+		}
+	}
+}
+
+func TestTranscriptScanner_CalculateWindow(t *testing.T) {
+	projectsDir := t.TempDir()
+	projDir := filepath.Join(projectsDir, "-Users-test")
+	_ = os.MkdirAll(projDir, 0755)
+
+	// One request now, and one before the start of this month.
+	now := time.Now()
+	lastMonth := Window{Calendar: Month}.Start(now).Add(-time.Hour)
+	line := `{"type":"assistant","message":{"model":"claude-opus-4-5-20251101","usage":{"input_tokens":1000,"output_tokens":500,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}},"timestamp":"%s"}` + "\n"
+	_ = os.WriteFile(filepath.Join(projDir, "s1.jsonl"), []byte(
+		fmt.Sprintf(line, now.UTC().Format(time.RFC3339Nano))+
+			fmt.Sprintf(line, lastMonth.UTC().Format(time.RFC3339Nano)),
+	), 0644)
+
+	scanner := NewTranscriptScanner(projectsDir, cache.New(t.TempDir()))
+	if got := scanner.CalculateWindow(Window{Calendar: Month}); got < 0.0174 || got > 0.0176 {
+		t.Errorf("expected only this month's request ($0.0175), got %f", got)
+	}
+	// A window reaching back past the month start gets its own cache entry.
+	if got := scanner.CalculateWindow(Window{Rolling: now.Sub(lastMonth) + time.Hour}); got < 0.0349 || got > 0.0351 {
+		t.Errorf("expected both requests ($0.035), got %f", got)
+	}
+}
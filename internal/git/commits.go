@@ -0,0 +1,80 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/authors"
+)
+
+// CommitQuery selects the commits CountCommits counts.
+type CommitQuery struct {
+	// Since is the start of the window; commits with an earlier committer
+	// date are not counted.
+	Since time.Time
+
+	// Authors restricts the count to commits whose author or any
+	// Co-authored-by trailer matches one of these patterns, ignoring case.
+	// A pattern containing "@" is an email and must equal the address
+	// between <>; any other pattern need only be contained in
+	// "Name <email>". The special value "me" stands for the configured
+	// user.email. Empty counts every author.
+	Authors []string
+
+	// AllBranches counts commits reachable from any local branch instead of
+	// only HEAD.
+	AllBranches bool
+}
+
+// StartOfDay returns local midnight at the start of t's day.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns local midnight on the Monday of t's week.
+func StartOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return StartOfDay(t).AddDate(0, 0, -daysSinceMonday)
+}
+
+// CountCommits counts the commits in the repo at dir matching q. Without
+// author filters it is a single `git rev-list --count`; with them, commit
+// headers and trailers are listed and matched here, since git's --author
+// cannot see trailers.
+func CountCommits(dir string, q CommitQuery) (int, error) {
+	args := []string{"--since=" + q.Since.Format(time.RFC3339)}
+	if q.AllBranches {
+		args = append(args, "--branches")
+	} else {
+		args = append(args, "HEAD")
+	}
+
+	patterns := authors.Resolve(q.Authors, func() string {
+		email, _ := run(dir, "config", "user.email")
+		return strings.TrimSpace(string(email))
+	})
+	if len(q.Authors) > 0 && len(patterns) == 0 {
+		// Only "me" was asked for and no user.email is configured.
+		return 0, nil
+	}
+	if len(patterns) == 0 {
+		output, err := run(dir, append([]string{"rev-list", "--count"}, args...)...)
+		if err != nil {
+			return 0, err
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(string(output)))
+		if err != nil {
+			return 0, fmt.Errorf("parse count failed: %w", err)
+		}
+		return count, nil
+	}
+
+	output, err := run(dir, append([]string{"log", "--format=%an <%ae>%x00%B%x00"}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	return authors.CountMatching(output, patterns), nil
+}
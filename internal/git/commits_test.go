@@ -0,0 +1,74 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// commitAs records an empty commit with the given author and message.
+func commitAs(t *testing.T, dir, author, message string) {
+	t.Helper()
+	mustRun(t, gitCmd(dir, "commit", "--no-verify", "--allow-empty", "-q", "--author", author, "-m", message))
+}
+
+func TestCountCommits(t *testing.T) {
+	dir := setupGitRepo(t) // "initial" by Test User <test@test.com>
+	commitAs(t, dir, "Alice <alice@example.com>", "alice's change")
+	commitAs(t, dir, "Bob <bob@example.com>", "pair work\n\nCo-Authored-By: Claude <noreply@anthropic.com>")
+	mustRun(t, gitCmd(dir, "checkout", "-q", "-b", "side"))
+	commitAs(t, dir, "Alice <alice@example.com>", "side work")
+	mustRun(t, gitCmd(dir, "checkout", "-q", "-"))
+
+	today := StartOfDay(time.Now())
+	tests := []struct {
+		name string
+		q    CommitQuery
+		want int
+	}{
+		{"all authors on HEAD", CommitQuery{Since: today}, 3},
+		{"all branches", CommitQuery{Since: today, AllBranches: true}, 4},
+		{"by name", CommitQuery{Since: today, Authors: []string{"alice"}}, 1},
+		{"by name across branches", CommitQuery{Since: today, Authors: []string{"ALICE"}, AllBranches: true}, 2},
+		{"co-author trailer", CommitQuery{Since: today, Authors: []string{"noreply@anthropic.com"}}, 1},
+		{"me is user.email", CommitQuery{Since: today, Authors: []string{"me"}}, 1},
+		{"several authors", CommitQuery{Since: today, Authors: []string{"me", "bob"}}, 2},
+		{"future window", CommitQuery{Since: time.Now().Add(time.Hour)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CountCommits(dir, tt.q)
+			if err != nil {
+				t.Fatalf("CountCommits failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountCommits_MeWithoutEmail(t *testing.T) {
+	dir := t.TempDir()
+	mustRun(t, gitCmd(dir, "init", "-q"))
+	_ = os.WriteFile(filepath.Join(dir, "f.txt"), []byte("f"), 0644)
+	mustRun(t, gitCmd(dir, "add", "f.txt"))
+	mustRun(t, gitCmd(dir, "-c", "user.email=x@y", "-c", "user.name=X", "commit", "--no-verify", "-qm", "f"))
+
+	got, err := CountCommits(dir, CommitQuery{Since: StartOfDay(time.Now()), Authors: []string{"me"}})
+	if err != nil || got != 0 {
+		t.Errorf("expected 0 commits without user.email, got %d (%v)", got, err)
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	sunday := time.Date(2025, 6, 8, 10, 0, 0, 0, time.Local)
+	if got, want := StartOfWeek(sunday), time.Date(2025, 6, 2, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Errorf("StartOfWeek(Sunday) = %v, want %v", got, want)
+	}
+	monday := time.Date(2025, 6, 2, 0, 30, 0, 0, time.Local)
+	if got := StartOfWeek(monday); !got.Equal(StartOfDay(monday)) {
+		t.Errorf("StartOfWeek(Monday) = %v, want same day", got)
+	}
+}
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+)
+
+// emptyTree is the hash of git's empty tree, used as the diff base before the
+// first commit so new files still count.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// DiffStat totals `git diff --numstat HEAD`: every staged and unstaged change
+// to tracked files relative to the last commit. Untracked files are not
+// included, matching git's own diff.
+type DiffStat struct {
+	Files   int
+	Added   int
+	Removed int
+	Binary  int // files counted in Files whose line counts git cannot report
+}
+
+// GetDiffStat diffs the working tree of the repo at dir against HEAD, or
+// against the empty tree when there are no commits yet.
+func GetDiffStat(dir string) (*DiffStat, error) {
+	base := "HEAD"
+	if repo, err := FindRepo(dir); err == nil {
+		if _, oid, err := repo.Head(); err == nil && oid == "" {
+			base = emptyTree
+		}
+	}
+	output, err := run(dir, "diff", "--numstat", base)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(output), nil
+}
+
+// parseNumstat parses "<added>\t<removed>\t<path>" lines, where binary files
+// report "-" for both counts.
+func parseNumstat(output []byte) *DiffStat {
+	d := &DiffStat{}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		d.Files++
+		if fields[0] == "-" && fields[1] == "-" {
+			d.Binary++
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		d.Added += added
+		d.Removed += removed
+	}
+	return d
+}
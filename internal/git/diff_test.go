@@ -0,0 +1,51 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	output := []byte("10\t2\tmain.go\n0\t5\tREADME.md\n-\t-\tassets/logo.png\n")
+	d := parseNumstat(output)
+	if d.Files != 3 || d.Added != 10 || d.Removed != 7 || d.Binary != 1 {
+		t.Errorf("unexpected diff stat %+v", d)
+	}
+}
+
+func TestGetDiffStat(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	if d, err := GetDiffStat(dir); err != nil || d.Files != 0 {
+		t.Fatalf("expected a clean diff, got %+v (%v)", d, err)
+	}
+
+	_ = os.WriteFile(filepath.Join(dir, "test.txt"), []byte("line one\nline two\n"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "staged.txt"), []byte("new\n"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("ignored\n"), 0644)
+	mustRun(t, gitCmd(dir, "add", "staged.txt"))
+
+	d, err := GetDiffStat(dir)
+	if err != nil {
+		t.Fatalf("GetDiffStat failed: %v", err)
+	}
+	if d.Files != 2 || d.Added != 3 || d.Removed != 1 {
+		t.Errorf("expected 2 files +3 -1, got %+v", d)
+	}
+}
+
+func TestGetDiffStat_NoCommits(t *testing.T) {
+	dir := t.TempDir()
+	mustRun(t, gitCmd(dir, "init", "-q"))
+	_ = os.WriteFile(filepath.Join(dir, "first.txt"), []byte("a\nb\n"), 0644)
+	mustRun(t, gitCmd(dir, "add", "first.txt"))
+
+	d, err := GetDiffStat(dir)
+	if err != nil {
+		t.Fatalf("GetDiffStat failed: %v", err)
+	}
+	if d.Files != 1 || d.Added != 2 {
+		t.Errorf("expected 1 file +2 before the first commit, got %+v", d)
+	}
+}
@@ -0,0 +1,14 @@
+package git
+
+import "github.com/h2ik/claude-statusline/internal/command"
+
+// ErrTimeout is returned (wrapped) when a git subprocess is killed for
+// running longer than the command timeout (see command.SetTimeout).
+var ErrTimeout = command.ErrTimeout
+
+// run executes git with args in dir under the command timeout and returns
+// its stdout. Failures include git's stderr; a killed command wraps
+// ErrTimeout so callers can tell "slow" apart from "failed".
+func run(dir string, args ...string) ([]byte, error) {
+	return command.Run(dir, "git", args...)
+}
@@ -2,86 +2,88 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// GetBranch returns the current branch name for the git repo at dir.
-func GetBranch(dir string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cmd.Dir = dir
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("git rev-parse failed: %w", err)
-	}
-	return strings.TrimSpace(string(output)), nil
+// Commit describes the HEAD commit for display: identity, author, subject,
+// and the nearest tag reachable from it.
+type Commit struct {
+	Hash      string
+	ShortHash string
+	Time      time.Time // committer date
+	Author    string
+	Subject   string
+
+	// Tag is the nearest tag per `git describe --tags`, empty when no tag is
+	// reachable; TagDistance counts the commits made since it.
+	Tag         string
+	TagDistance int
 }
 
-// IsClean returns true if the working tree has no uncommitted changes.
-func IsClean(dir string) (bool, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = dir
-	output, err := cmd.Output()
+// GetLastCommit returns the HEAD commit of the repo at dir along with its
+// nearest tag. It fails on a repository without commits.
+func GetLastCommit(dir string) (*Commit, error) {
+	output, err := run(dir, "log", "-1", "--format=%H%x00%h%x00%ct%x00%an%x00%s")
 	if err != nil {
-		return false, fmt.Errorf("git status failed: %w", err)
+		return nil, err
+	}
+	fields := strings.SplitN(strings.TrimRight(string(output), "\n"), "\x00", 5)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("parse git log output: %q", output)
+	}
+	unix, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse commit time: %w", err)
 	}
-	return len(strings.TrimSpace(string(output))) == 0, nil
-}
 
-// IsGitRepo returns true if dir is inside a git repository.
-func IsGitRepo(dir string) bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = dir
-	return cmd.Run() == nil
+	c := &Commit{
+		Hash:      fields[0],
+		ShortHash: fields[1],
+		Time:      time.Unix(unix, 0),
+		Author:    fields[3],
+		Subject:   fields[4],
+	}
+	if described, err := run(dir, "describe", "--tags", "--long"); err == nil {
+		c.Tag, c.TagDistance = parseDescribe(strings.TrimSpace(string(described)))
+	}
+	return c, nil
 }
 
-// GetCommitsToday returns the number of commits made today on the current branch.
-func GetCommitsToday(dir string) (int, error) {
-	cmd := exec.Command("git", "rev-list", "--count", "--since=today 00:00", "HEAD")
-	cmd.Dir = dir
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, fmt.Errorf("git rev-list failed: %w", err)
+// parseDescribe splits `git describe --long` output of the form
+// "<tag>-<distance>-g<hash>"; the tag itself may contain dashes.
+func parseDescribe(s string) (tag string, distance int) {
+	rest, _, ok := cutLast(s, "-g")
+	if !ok {
+		return s, 0
 	}
-	var count int
-	if _, err := fmt.Sscanf(strings.TrimSpace(string(output)), "%d", &count); err != nil {
-		return 0, fmt.Errorf("parse count failed: %w", err)
+	tag, n, ok := cutLast(rest, "-")
+	if !ok {
+		return s, 0
 	}
-	return count, nil
+	distance, _ = strconv.Atoi(n)
+	return tag, distance
 }
 
-// GetSubmoduleCount returns the number of git submodules in the repo.
-func GetSubmoduleCount(dir string) (int, error) {
-	cmd := exec.Command("git", "submodule", "status")
-	cmd.Dir = dir
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, nil
-	}
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 1 && lines[0] == "" {
-		return 0, nil
+// cutLast is strings.Cut around the last occurrence of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
 	}
-	return len(lines), nil
+	return s, "", false
 }
 
-// IsWorktree returns whether the repo at dir is a git worktree, and if so, the worktree name.
-func IsWorktree(dir string) (bool, string, error) {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = dir
-	output, err := cmd.Output()
+// GetSubmoduleCount returns the number of git submodules declared in the
+// repo's .gitmodules.
+func GetSubmoduleCount(dir string) (int, error) {
+	repo, err := FindRepo(dir)
 	if err != nil {
-		return false, "", err
+		return 0, nil
 	}
-	gitDir := strings.TrimSpace(string(output))
-	if strings.Contains(gitDir, ".git/worktrees/") {
-		parts := strings.Split(gitDir, "/")
-		for i, part := range parts {
-			if part == "worktrees" && i+1 < len(parts) {
-				return true, parts[i+1], nil
-			}
-		}
-		return true, "", nil
+	subs, err := repo.Submodules()
+	if err != nil {
+		return 0, nil
 	}
-	return false, "", nil
+	return len(subs), nil
 }
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // gitCmd creates an exec.Command for git that is isolated from the user's
@@ -58,86 +59,86 @@ func setupGitRepo(t *testing.T) string {
 	return dir
 }
 
-func TestGetBranch(t *testing.T) {
+func TestGetSubmoduleCount(t *testing.T) {
 	dir := setupGitRepo(t)
-	branch, err := GetBranch(dir)
+
+	count, err := GetSubmoduleCount(dir)
 	if err != nil {
-		t.Fatalf("GetBranch failed: %v", err)
+		t.Fatalf("GetSubmoduleCount failed: %v", err)
 	}
-	if branch != "main" && branch != "master" {
-		t.Logf("got branch: %s (acceptable)", branch)
+	if count != 0 {
+		t.Errorf("expected 0 submodules, got %d", count)
 	}
 }
 
-func TestIsClean(t *testing.T) {
+func TestTakeSnapshot(t *testing.T) {
 	dir := setupGitRepo(t)
 
-	clean, err := IsClean(dir)
-	if err != nil {
-		t.Fatalf("IsClean failed: %v", err)
-	}
-	if !clean {
-		t.Error("expected clean status")
+	snap := TakeSnapshot(dir, Options{})
+	if !snap.IsRepo {
+		t.Fatal("expected IsRepo=true")
 	}
-
-	testFile := filepath.Join(dir, "test.txt")
-	_ = os.WriteFile(testFile, []byte("modified"), 0644)
-
-	clean, err = IsClean(dir)
-	if err != nil {
-		t.Fatalf("IsClean failed: %v", err)
+	if snap.Branch == "" {
+		t.Error("expected a branch name")
 	}
-	if clean {
-		t.Error("expected dirty status after modification")
+	if !snap.Clean() {
+		t.Error("expected clean snapshot")
 	}
-}
-
-func TestIsGitRepo(t *testing.T) {
-	dir := setupGitRepo(t)
-
-	if !IsGitRepo(dir) {
-		t.Error("expected true for a git repo")
+	if snap.IsWorktree {
+		t.Error("expected non-worktree snapshot")
 	}
 
-	nonRepo := t.TempDir()
-	if IsGitRepo(nonRepo) {
-		t.Error("expected false for a non-git directory")
+	if s := TakeSnapshot(t.TempDir(), Options{}); s.IsRepo || s.Branch != "" {
+		t.Errorf("expected empty snapshot outside a repo, got %+v", s)
 	}
 }
 
-func TestGetCommitsToday(t *testing.T) {
+func TestGetLastCommit(t *testing.T) {
 	dir := setupGitRepo(t)
 
-	count, err := GetCommitsToday(dir)
+	c, err := GetLastCommit(dir)
 	if err != nil {
-		t.Fatalf("GetCommitsToday failed: %v", err)
+		t.Fatalf("GetLastCommit failed: %v", err)
 	}
-	// We just made a commit, so count should be at least 1
-	if count < 1 {
-		t.Errorf("expected at least 1 commit today, got %d", count)
+	if c.Subject != "initial" || c.Author != "Test User" || len(c.ShortHash) < 7 || c.Tag != "" {
+		t.Errorf("unexpected commit %+v", c)
+	}
+	if age := time.Since(c.Time); age < 0 || age > time.Hour {
+		t.Errorf("unexpected commit time %v", c.Time)
 	}
-}
 
-func TestGetSubmoduleCount(t *testing.T) {
-	dir := setupGitRepo(t)
+	mustRun(t, gitCmd(dir, "tag", "-a", "release-1.0", "-m", "release"))
+	_ = os.WriteFile(filepath.Join(dir, "next.txt"), []byte("next"), 0644)
+	mustRun(t, gitCmd(dir, "add", "next.txt"))
+	mustRun(t, gitCmd(dir, "commit", "--no-verify", "-qm", "next"))
 
-	count, err := GetSubmoduleCount(dir)
+	c, err = GetLastCommit(dir)
 	if err != nil {
-		t.Fatalf("GetSubmoduleCount failed: %v", err)
+		t.Fatalf("GetLastCommit failed: %v", err)
 	}
-	if count != 0 {
-		t.Errorf("expected 0 submodules, got %d", count)
+	if c.Subject != "next" || c.Tag != "release-1.0" || c.TagDistance != 1 {
+		t.Errorf("expected 'next' one commit after release-1.0, got %+v", c)
 	}
-}
-
-func TestIsWorktree(t *testing.T) {
-	dir := setupGitRepo(t)
 
-	isWt, _, err := IsWorktree(dir)
-	if err != nil {
-		t.Fatalf("IsWorktree failed: %v", err)
+	if _, err := GetLastCommit(t.TempDir()); err == nil {
+		t.Error("expected error outside a repository")
 	}
-	if isWt {
-		t.Error("expected false for a non-worktree repo")
+}
+
+func TestParseDescribe(t *testing.T) {
+	tests := []struct {
+		in       string
+		tag      string
+		distance int
+	}{
+		{"v1.2.0-0-g1234567", "v1.2.0", 0},
+		{"release-1.0-12-gabcdef0", "release-1.0", 12},
+		{"odd", "odd", 0},
+	}
+	for _, tt := range tests {
+		tag, distance := parseDescribe(tt.in)
+		if tag != tt.tag || distance != tt.distance {
+			t.Errorf("parseDescribe(%q) = (%q, %d), want (%q, %d)", tt.in, tag, distance, tt.tag, tt.distance)
+		}
 	}
 }
@@ -0,0 +1,45 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Options tunes how TakeSnapshot inspects a repository.
+type Options struct {
+	// LargeRepoPaths lists directories whose repositories always use
+	// large-repo mode: the repository's work tree must be one of them or
+	// sit underneath one.
+	LargeRepoPaths []string
+
+	// LargeRepoIndexSize enables large-repo mode automatically when the
+	// index file is at least this many bytes. Zero or negative disables
+	// auto-detection.
+	LargeRepoIndexSize int64
+}
+
+// IsLarge reports whether r should be inspected in large-repo mode, where
+// git status skips the untracked-file scan.
+func (o Options) IsLarge(r *Repo) bool {
+	for _, p := range o.LargeRepoPaths {
+		if p != "" && within(r.WorkTree, p) {
+			return true
+		}
+	}
+	if o.LargeRepoIndexSize > 0 {
+		if info, err := os.Stat(filepath.Join(r.GitDir, "index")); err == nil {
+			return info.Size() >= o.LargeRepoIndexSize
+		}
+	}
+	return false
+}
+
+// within reports whether path is dir or lies beneath it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,52 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOptionsIsLarge(t *testing.T) {
+	dir := setupGitRepo(t)
+	repo, err := FindRepo(dir)
+	if err != nil {
+		t.Fatalf("FindRepo failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want bool
+	}{
+		{"no options", Options{}, false},
+		{"exact path", Options{LargeRepoPaths: []string{repo.WorkTree}}, true},
+		{"parent path", Options{LargeRepoPaths: []string{filepath.Dir(repo.WorkTree)}}, true},
+		{"sibling prefix", Options{LargeRepoPaths: []string{repo.WorkTree + "-other"}}, false},
+		{"index over threshold", Options{LargeRepoIndexSize: 1}, true},
+		{"index under threshold", Options{LargeRepoIndexSize: 1 << 30}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.IsLarge(repo); got != tt.want {
+				t.Errorf("IsLarge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTakeSnapshot_LargeRepo(t *testing.T) {
+	dir := setupGitRepo(t)
+	_ = os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("new"), 0644)
+
+	snap := TakeSnapshot(dir, Options{LargeRepoIndexSize: 1})
+	if !snap.LargeRepo {
+		t.Fatal("expected large-repo mode")
+	}
+	if snap.Untracked != 0 || !snap.Clean() {
+		t.Errorf("expected untracked files to be skipped, got %+v", snap.Status)
+	}
+
+	if snap := TakeSnapshot(dir, Options{}); snap.LargeRepo || snap.Untracked != 1 {
+		t.Errorf("expected normal mode to count untracked, got %+v", snap.Status)
+	}
+}
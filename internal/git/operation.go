@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Operation describes a multi-step git command that is in progress in a
+// repository, such as a rebase stopped on a conflict.
+type Operation struct {
+	Kind   string // "REBASE", "AM", "AM/REBASE", "MERGE", "CHERRY-PICK", "REVERT", or "BISECT"
+	Step   int    // current step for rebase/am, 0 when not applicable
+	Total  int    // total steps for rebase/am, 0 when not applicable
+	Branch string // branch being rebased, when known
+}
+
+// String renders the operation as shown in the statusline, e.g. "REBASE 3/7".
+func (o *Operation) String() string {
+	if o.Total > 0 {
+		return o.Kind + " " + strconv.Itoa(o.Step) + "/" + strconv.Itoa(o.Total)
+	}
+	return o.Kind
+}
+
+// DetectOperation inspects the state files git leaves in gitDir while an
+// operation is in progress and returns nil when there is none. gitDir must be
+// the per-worktree git directory (what `git rev-parse --absolute-git-dir`
+// prints), since linked worktrees keep these files in .git/worktrees/<name>.
+// The checks mirror git's own contrib/completion/git-prompt.sh.
+func DetectOperation(gitDir string) *Operation {
+	if gitDir == "" {
+		return nil
+	}
+
+	if dir := filepath.Join(gitDir, "rebase-merge"); isDir(dir) {
+		return &Operation{
+			Kind:   "REBASE",
+			Step:   readInt(filepath.Join(dir, "msgnum")),
+			Total:  readInt(filepath.Join(dir, "end")),
+			Branch: strings.TrimPrefix(readTrimmed(filepath.Join(dir, "head-name")), "refs/heads/"),
+		}
+	}
+
+	if dir := filepath.Join(gitDir, "rebase-apply"); isDir(dir) {
+		op := &Operation{
+			Kind:  "AM/REBASE",
+			Step:  readInt(filepath.Join(dir, "next")),
+			Total: readInt(filepath.Join(dir, "last")),
+		}
+		switch {
+		case exists(filepath.Join(dir, "rebasing")):
+			op.Kind = "REBASE"
+			op.Branch = strings.TrimPrefix(readTrimmed(filepath.Join(dir, "head-name")), "refs/heads/")
+		case exists(filepath.Join(dir, "applying")):
+			op.Kind = "AM"
+		}
+		return op
+	}
+
+	for _, marker := range []struct {
+		file string
+		kind string
+	}{
+		{"MERGE_HEAD", "MERGE"},
+		{"CHERRY_PICK_HEAD", "CHERRY-PICK"},
+		{"REVERT_HEAD", "REVERT"},
+		{"BISECT_LOG", "BISECT"},
+	} {
+		if exists(filepath.Join(gitDir, marker.file)) {
+			return &Operation{Kind: marker.kind}
+		}
+	}
+
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readInt(path string) int {
+	n, _ := strconv.Atoi(readTrimmed(path))
+	return n
+}
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeGitFile(t *testing.T, gitDir, name, content string) {
+	t.Helper()
+	path := filepath.Join(gitDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s failed: %v", name, err)
+	}
+}
+
+func TestDetectOperation(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"none", nil, ""},
+		{"interactive rebase", map[string]string{
+			"rebase-merge/msgnum":    "3\n",
+			"rebase-merge/end":       "7\n",
+			"rebase-merge/head-name": "refs/heads/feature\n",
+		}, "REBASE 3/7"},
+		{"apply rebase", map[string]string{
+			"rebase-apply/next":     "2",
+			"rebase-apply/last":     "5",
+			"rebase-apply/rebasing": "",
+		}, "REBASE 2/5"},
+		{"am", map[string]string{
+			"rebase-apply/next":     "1",
+			"rebase-apply/last":     "4",
+			"rebase-apply/applying": "",
+		}, "AM 1/4"},
+		{"merge", map[string]string{"MERGE_HEAD": "abc\n"}, "MERGE"},
+		{"cherry-pick", map[string]string{"CHERRY_PICK_HEAD": "abc\n"}, "CHERRY-PICK"},
+		{"revert", map[string]string{"REVERT_HEAD": "abc\n"}, "REVERT"},
+		{"bisect", map[string]string{"BISECT_LOG": "git bisect start\n"}, "BISECT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitDir := t.TempDir()
+			for name, content := range tt.files {
+				writeGitFile(t, gitDir, name, content)
+			}
+
+			op := DetectOperation(gitDir)
+			got := ""
+			if op != nil {
+				got = op.String()
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDetectOperation_RebaseBranch(t *testing.T) {
+	gitDir := t.TempDir()
+	writeGitFile(t, gitDir, "rebase-merge/head-name", "refs/heads/feature/login\n")
+
+	op := DetectOperation(gitDir)
+	if op == nil || op.Branch != "feature/login" {
+		t.Errorf("expected branch feature/login, got %+v", op)
+	}
+}
+
+func TestTakeSnapshot_DetachedAtTag(t *testing.T) {
+	dir := setupGitRepo(t)
+	if err := gitCmd(dir, "tag", "v1.0.0").Run(); err != nil {
+		t.Fatalf("git tag failed: %v", err)
+	}
+	if out, err := gitCmd(dir, "checkout", "--detach", "HEAD").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout failed: %v\n%s", err, out)
+	}
+
+	snap := TakeSnapshot(dir, Options{})
+	if !snap.Detached || snap.Head != "v1.0.0" {
+		t.Errorf("expected detached head labelled v1.0.0, got %q (detached=%v)", snap.Head, snap.Detached)
+	}
+}
+
+func TestTakeSnapshot_DetachedShortHash(t *testing.T) {
+	dir := setupGitRepo(t)
+	if out, err := gitCmd(dir, "checkout", "--detach", "HEAD").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout failed: %v\n%s", err, out)
+	}
+
+	snap := TakeSnapshot(dir, Options{})
+	if len(snap.Head) != 7 || !strings.HasPrefix(snap.Oid, snap.Head) {
+		t.Errorf("expected 7-char prefix of %q, got %q", snap.Oid, snap.Head)
+	}
+}
+
+func TestTakeSnapshot_MergeInProgress(t *testing.T) {
+	dir := setupGitRepo(t)
+	base := strings.TrimSpace(string(mustOutput(t, gitCmd(dir, "rev-parse", "--abbrev-ref", "HEAD"))))
+
+	mustRun(t, gitCmd(dir, "checkout", "-b", "other"))
+	_ = os.WriteFile(filepath.Join(dir, "test.txt"), []byte("other"), 0644)
+	mustRun(t, gitCmd(dir, "commit", "--no-verify", "-am", "other"))
+	mustRun(t, gitCmd(dir, "checkout", base))
+	_ = os.WriteFile(filepath.Join(dir, "test.txt"), []byte("base"), 0644)
+	mustRun(t, gitCmd(dir, "commit", "--no-verify", "-am", "base"))
+	_ = gitCmd(dir, "merge", "other").Run() // conflicts by design
+
+	snap := TakeSnapshot(dir, Options{})
+	if snap.Operation == nil || snap.Operation.Kind != "MERGE" {
+		t.Fatalf("expected MERGE in progress, got %+v", snap.Operation)
+	}
+	if snap.Conflicted != 1 {
+		t.Errorf("expected 1 conflicted path, got %d", snap.Conflicted)
+	}
+}
+
+func mustRun(t *testing.T, cmd *exec.Cmd) {
+	t.Helper()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("command failed: %v\n%s", err, out)
+	}
+}
+
+func mustOutput(t *testing.T, cmd *exec.Cmd) []byte {
+	t.Helper()
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	return out
+}
@@ -0,0 +1,384 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepo is returned by FindRepo when no repository encloses a directory.
+var ErrNotRepo = errors.New("not a git repository")
+
+// Repo is the on-disk layout of a git repository, resolved by reading files
+// under .git directly instead of running git. It covers what the statusline
+// needs on every render -- HEAD, refs, worktree identity, submodules, config --
+// so the git binary is only spawned for work that truly needs it, such as
+// dirty detection.
+type Repo struct {
+	WorkTree  string // top-level directory of the checkout
+	GitDir    string // per-worktree git directory (.git, or .git/worktrees/<name>)
+	CommonDir string // shared git directory holding refs, objects, and config
+}
+
+// FindRepo walks up from dir to the nearest checkout, following `gitdir:`
+// files used by linked worktrees and submodules.
+func FindRepo(dir string) (*Repo, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				if gitDir, err = readGitdirFile(dotGit); err != nil {
+					return nil, err
+				}
+			}
+			if isDir(gitDir) && exists(filepath.Join(gitDir, "HEAD")) {
+				return &Repo{WorkTree: dir, GitDir: gitDir, CommonDir: commonDir(gitDir)}, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, ErrNotRepo
+		}
+		dir = parent
+	}
+}
+
+// readGitdirFile resolves a ".git" file of the form "gitdir: <path>", where
+// a relative path is relative to the file's directory.
+func readGitdirFile(path string) (string, error) {
+	content := readTrimmed(path)
+	target, ok := strings.CutPrefix(content, "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("%s: missing gitdir line", path)
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return filepath.Clean(target), nil
+}
+
+// commonDir returns the shared git directory for gitDir. Linked worktrees
+// record it in a "commondir" file; everything else is its own common dir.
+func commonDir(gitDir string) string {
+	rel := readTrimmed(filepath.Join(gitDir, "commondir"))
+	if rel == "" {
+		return gitDir
+	}
+	if filepath.IsAbs(rel) {
+		return filepath.Clean(rel)
+	}
+	return filepath.Clean(filepath.Join(gitDir, rel))
+}
+
+// IsWorktree reports whether this checkout is a linked worktree, and its name.
+func (r *Repo) IsWorktree() (bool, string) {
+	if r.GitDir == r.CommonDir {
+		return false, ""
+	}
+	return true, filepath.Base(r.GitDir)
+}
+
+// Head reads HEAD. For a checked-out branch it returns the short branch name
+// and the commit it points at (empty on an unborn branch). For a detached
+// HEAD, branch is empty and oid is the commit hash.
+func (r *Repo) Head() (branch, oid string, err error) {
+	content := readTrimmed(filepath.Join(r.GitDir, "HEAD"))
+	if content == "" {
+		return "", "", fmt.Errorf("read HEAD: empty or missing")
+	}
+	if ref, ok := strings.CutPrefix(content, "ref:"); ok {
+		ref = strings.TrimSpace(ref)
+		oid, _ = r.ResolveRef(ref)
+		return strings.TrimPrefix(ref, "refs/heads/"), oid, nil
+	}
+	return "", content, nil
+}
+
+// RefFile returns the file that currently holds the commit of the checked-out
+// branch: its loose ref file, or packed-refs when the branch is only packed.
+// Returns "" for a detached HEAD, whose commit lives in HEAD itself.
+func (r *Repo) RefFile() string {
+	content := readTrimmed(filepath.Join(r.GitDir, "HEAD"))
+	ref, ok := strings.CutPrefix(content, "ref:")
+	if !ok {
+		return ""
+	}
+	ref = filepath.FromSlash(strings.TrimSpace(ref))
+	for _, dir := range []string{r.GitDir, r.CommonDir} {
+		if path := filepath.Join(dir, ref); exists(path) {
+			return path
+		}
+	}
+	return filepath.Join(r.CommonDir, "packed-refs")
+}
+
+// ResolveRef resolves a full ref name such as "refs/heads/main" to a commit
+// hash, checking loose refs (per-worktree first, then shared) before
+// packed-refs and following symbolic refs a few levels deep.
+func (r *Repo) ResolveRef(name string) (string, error) {
+	for depth := 0; depth < 5; depth++ {
+		content := ""
+		for _, dir := range []string{r.GitDir, r.CommonDir} {
+			if c := readTrimmed(filepath.Join(dir, filepath.FromSlash(name))); c != "" {
+				content = c
+				break
+			}
+		}
+		if content == "" {
+			if oid, ok := r.packedRefs()[name]; ok {
+				return oid.oid, nil
+			}
+			return "", fmt.Errorf("ref %s not found", name)
+		}
+		next, ok := strings.CutPrefix(content, "ref:")
+		if !ok {
+			return content, nil
+		}
+		name = strings.TrimSpace(next)
+	}
+	return "", fmt.Errorf("ref %s: too many levels of symbolic refs", name)
+}
+
+// packedRef is one packed-refs entry; peeled is set for annotated tags.
+type packedRef struct {
+	oid    string
+	peeled string
+}
+
+// packedRefs parses CommonDir/packed-refs, returning nil if it is absent.
+func (r *Repo) packedRefs() map[string]packedRef {
+	data, err := os.ReadFile(filepath.Join(r.CommonDir, "packed-refs"))
+	if err != nil {
+		return nil
+	}
+
+	refs := make(map[string]packedRef)
+	last := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case line[0] == '^':
+			if last != "" {
+				e := refs[last]
+				e.peeled = line[1:]
+				refs[last] = e
+			}
+		default:
+			oid, name, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			refs[name] = packedRef{oid: oid}
+			last = name
+		}
+	}
+	return refs
+}
+
+// TagAt returns the name of a tag pointing at oid, or "" if none does.
+// Lightweight tags and annotated tags are both recognized; annotated tags are
+// peeled via packed-refs or, for loose tags, by reading the tag object.
+// When several tags match, the lexically smallest is returned for stability.
+func (r *Repo) TagAt(oid string) string {
+	if oid == "" {
+		return ""
+	}
+
+	var matches []string
+	for name, ref := range r.packedRefs() {
+		if tag, ok := strings.CutPrefix(name, "refs/tags/"); ok && (ref.oid == oid || ref.peeled == oid) {
+			matches = append(matches, tag)
+		}
+	}
+
+	tagsDir := filepath.Join(r.CommonDir, "refs", "tags")
+	_ = filepath.WalkDir(tagsDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		target := readTrimmed(path)
+		if target == oid || r.peelTag(target) == oid {
+			rel, _ := filepath.Rel(tagsDir, path)
+			matches = append(matches, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+
+	best := ""
+	for _, m := range matches {
+		if best == "" || m < best {
+			best = m
+		}
+	}
+	return best
+}
+
+// peelTag returns the object an annotated tag points at, reading the loose
+// tag object from disk. Returns "" if oid is not a loose tag object (packed
+// objects are not parsed).
+func (r *Repo) peelTag(oid string) string {
+	if len(oid) < 3 {
+		return ""
+	}
+	f, err := os.Open(filepath.Join(r.CommonDir, "objects", oid[:2], oid[2:]))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	zr, err := zlib.NewReader(f)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = zr.Close() }()
+
+	// Header "tag <size>\x00" followed by "object <oid>\n...". 512 bytes is
+	// plenty to reach the object line.
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(zr, buf)
+	header, body, ok := bytes.Cut(buf[:n], []byte{0})
+	if !ok || !bytes.HasPrefix(header, []byte("tag ")) {
+		return ""
+	}
+	line, _, _ := bytes.Cut(body, []byte{'\n'})
+	target, ok := bytes.CutPrefix(line, []byte("object "))
+	if !ok {
+		return ""
+	}
+	return string(target)
+}
+
+// Submodule is one entry from .gitmodules.
+type Submodule struct {
+	Name string
+	Path string
+	URL  string
+}
+
+// Submodules parses .gitmodules at the top of the working tree, in file
+// order. A missing file yields no submodules and no error.
+func (r *Repo) Submodules() ([]Submodule, error) {
+	entries, err := readConfigFile(filepath.Join(r.WorkTree, ".gitmodules"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var subs []Submodule
+	index := make(map[string]int)
+	for _, e := range entries {
+		if e.Section != "submodule" || e.Subsection == "" {
+			continue
+		}
+		i, ok := index[e.Subsection]
+		if !ok {
+			i = len(subs)
+			index[e.Subsection] = i
+			subs = append(subs, Submodule{Name: e.Subsection})
+		}
+		switch e.Key {
+		case "path":
+			subs[i].Path = e.Value
+		case "url":
+			subs[i].URL = e.Value
+		}
+	}
+	return subs, nil
+}
+
+// Config returns the entries of the repository's shared config file.
+func (r *Repo) Config() ([]ConfigEntry, error) {
+	return readConfigFile(filepath.Join(r.CommonDir, "config"))
+}
+
+// ConfigEntry is one key/value from a git config file. Section and Key are
+// lower-cased as git treats them case-insensitively; Subsection is verbatim.
+type ConfigEntry struct {
+	Section    string
+	Subsection string
+	Key        string
+	Value      string
+}
+
+// readConfigFile parses the subset of git's config syntax found in practice
+// in .git/config and .gitmodules: [section] and [section "sub"] headers,
+// key = value pairs, comments, and quoted values. Includes are not followed.
+func readConfigFile(path string) ([]ConfigEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []ConfigEntry
+	section, subsection := "", ""
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.LastIndex(line, "]")
+			if end < 0 {
+				continue
+			}
+			header := strings.TrimSpace(line[1:end])
+			name, sub, hasSub := strings.Cut(header, " ")
+			section = strings.ToLower(name)
+			subsection = ""
+			if hasSub {
+				subsection = strings.Trim(strings.TrimSpace(sub), `"`)
+			} else if dot := strings.Index(section, "."); dot >= 0 {
+				// Legacy [section.subsection] form.
+				section, subsection = section[:dot], section[dot+1:]
+			}
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if hasValue {
+			value = configValue(value)
+		} else {
+			value = "true"
+		}
+		entries = append(entries, ConfigEntry{Section: section, Subsection: subsection, Key: key, Value: value})
+	}
+	return entries, scanner.Err()
+}
+
+// configValue trims a raw config value, strips an unquoted trailing comment,
+// and removes surrounding double quotes.
+func configValue(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, `"`) {
+		if i := strings.IndexAny(raw, "#;"); i >= 0 {
+			raw = strings.TrimSpace(raw[:i])
+		}
+		return raw
+	}
+	if end := strings.LastIndex(raw, `"`); end > 0 {
+		return raw[1:end]
+	}
+	return strings.Trim(raw, `"`)
+}
@@ -0,0 +1,181 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func headOid(t *testing.T, dir string) string {
+	t.Helper()
+	return strings.TrimSpace(string(mustOutput(t, gitCmd(dir, "rev-parse", "HEAD"))))
+}
+
+func TestFindRepo_FromSubdirectory(t *testing.T) {
+	dir := setupGitRepo(t)
+	sub := filepath.Join(dir, "a", "b")
+	_ = os.MkdirAll(sub, 0755)
+
+	repo, err := FindRepo(sub)
+	if err != nil {
+		t.Fatalf("FindRepo failed: %v", err)
+	}
+	wantTop, _ := filepath.EvalSymlinks(dir)
+	gotTop, _ := filepath.EvalSymlinks(repo.WorkTree)
+	if gotTop != wantTop {
+		t.Errorf("work tree: got %q, want %q", gotTop, wantTop)
+	}
+	if repo.GitDir != repo.CommonDir {
+		t.Errorf("expected GitDir == CommonDir for a plain checkout, got %q / %q", repo.GitDir, repo.CommonDir)
+	}
+	if isWT, _ := repo.IsWorktree(); isWT {
+		t.Error("expected non-worktree")
+	}
+}
+
+func TestFindRepo_NotARepo(t *testing.T) {
+	if _, err := FindRepo(t.TempDir()); err != ErrNotRepo {
+		t.Errorf("expected ErrNotRepo, got %v", err)
+	}
+}
+
+func TestRepo_Head(t *testing.T) {
+	dir := setupGitRepo(t)
+	repo, _ := FindRepo(dir)
+
+	branch, oid, err := repo.Head()
+	if err != nil {
+		t.Fatalf("Head failed: %v", err)
+	}
+	want := strings.TrimSpace(string(mustOutput(t, gitCmd(dir, "rev-parse", "--abbrev-ref", "HEAD"))))
+	if branch != want {
+		t.Errorf("branch: got %q, want %q", branch, want)
+	}
+	if oid != headOid(t, dir) {
+		t.Errorf("oid: got %q, want %q", oid, headOid(t, dir))
+	}
+
+	mustRun(t, gitCmd(dir, "checkout", "--detach", "HEAD"))
+	branch, oid, _ = repo.Head()
+	if branch != "" || oid != headOid(t, dir) {
+		t.Errorf("expected detached head at %s, got branch=%q oid=%q", headOid(t, dir), branch, oid)
+	}
+}
+
+func TestRepo_ResolveRef_PackedRefs(t *testing.T) {
+	dir := setupGitRepo(t)
+	mustRun(t, gitCmd(dir, "branch", "packed-branch"))
+	mustRun(t, gitCmd(dir, "pack-refs", "--all"))
+
+	repo, _ := FindRepo(dir)
+	if _, err := os.Stat(filepath.Join(repo.CommonDir, "refs", "heads", "packed-branch")); !os.IsNotExist(err) {
+		t.Fatal("expected branch ref to be packed")
+	}
+
+	oid, err := repo.ResolveRef("refs/heads/packed-branch")
+	if err != nil {
+		t.Fatalf("ResolveRef failed: %v", err)
+	}
+	if oid != headOid(t, dir) {
+		t.Errorf("got %q, want %q", oid, headOid(t, dir))
+	}
+}
+
+func TestRepo_TagAt(t *testing.T) {
+	dir := setupGitRepo(t)
+	repo, _ := FindRepo(dir)
+	oid := headOid(t, dir)
+
+	if tag := repo.TagAt(oid); tag != "" {
+		t.Errorf("expected no tag, got %q", tag)
+	}
+
+	mustRun(t, gitCmd(dir, "tag", "-a", "v2.0.0", "-m", "annotated"))
+	if tag := repo.TagAt(oid); tag != "v2.0.0" {
+		t.Errorf("loose annotated tag: got %q, want v2.0.0", tag)
+	}
+
+	mustRun(t, gitCmd(dir, "pack-refs", "--all"))
+	if tag := repo.TagAt(oid); tag != "v2.0.0" {
+		t.Errorf("packed annotated tag: got %q, want v2.0.0", tag)
+	}
+
+	mustRun(t, gitCmd(dir, "tag", "v1.0.0"))
+	if tag := repo.TagAt(oid); tag != "v1.0.0" {
+		t.Errorf("expected lexically first tag v1.0.0, got %q", tag)
+	}
+}
+
+func TestRepo_LinkedWorktree(t *testing.T) {
+	dir := setupGitRepo(t)
+	wtDir := filepath.Join(t.TempDir(), "wt")
+	mustRun(t, gitCmd(dir, "worktree", "add", "-b", "wt-branch", wtDir))
+
+	repo, err := FindRepo(wtDir)
+	if err != nil {
+		t.Fatalf("FindRepo failed: %v", err)
+	}
+	isWT, name := repo.IsWorktree()
+	if !isWT || name != "wt" {
+		t.Errorf("expected worktree named wt, got %v %q", isWT, name)
+	}
+
+	branch, oid, _ := repo.Head()
+	if branch != "wt-branch" || oid != headOid(t, dir) {
+		t.Errorf("expected wt-branch at main's head, got %q %q", branch, oid)
+	}
+}
+
+func TestRepo_Submodules(t *testing.T) {
+	dir := setupGitRepo(t)
+	gitmodules := `[submodule "lib/one"]
+	path = lib/one
+	url = https://github.com/example/one.git
+[submodule "two"]
+	path = vendor/two
+	url = "git@gitlab.com:example/two.git" ; trailing comment
+`
+	_ = os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte(gitmodules), 0644)
+
+	repo, _ := FindRepo(dir)
+	subs, err := repo.Submodules()
+	if err != nil {
+		t.Fatalf("Submodules failed: %v", err)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("expected 2 submodules, got %d: %+v", len(subs), subs)
+	}
+	if subs[0].Name != "lib/one" || subs[0].Path != "lib/one" || subs[0].URL != "https://github.com/example/one.git" {
+		t.Errorf("unexpected first submodule: %+v", subs[0])
+	}
+	if subs[1].Path != "vendor/two" || subs[1].URL != "git@gitlab.com:example/two.git" {
+		t.Errorf("unexpected second submodule: %+v", subs[1])
+	}
+
+	count, _ := GetSubmoduleCount(dir)
+	if count != 2 {
+		t.Errorf("GetSubmoduleCount: got %d, want 2", count)
+	}
+}
+
+func TestRepo_Config(t *testing.T) {
+	dir := setupGitRepo(t)
+	mustRun(t, gitCmd(dir, "remote", "add", "origin", "https://github.com/h2ik/claude-statusline.git"))
+
+	repo, _ := FindRepo(dir)
+	entries, err := repo.Config()
+	if err != nil {
+		t.Fatalf("Config failed: %v", err)
+	}
+
+	found := false
+	for _, e := range entries {
+		if e.Section == "remote" && e.Subsection == "origin" && e.Key == "url" {
+			found = e.Value == "https://github.com/h2ik/claude-statusline.git"
+		}
+	}
+	if !found {
+		t.Errorf("expected remote.origin.url in %+v", entries)
+	}
+}
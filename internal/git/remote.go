@@ -0,0 +1,83 @@
+package git
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Remote describes the remote a branch tracks, with its URL broken down into
+// host and repository path. Host is empty for local-path remotes.
+type Remote struct {
+	Name  string
+	URL   string
+	Host  string
+	Owner string // everything before the repository name, e.g. "group/subgroup"
+	Repo  string
+}
+
+// UpstreamRemote returns the remote that branch tracks, read from the
+// branch.<name>.remote and remote.<name>.url config keys. It returns nil when
+// the branch has no upstream remote configured, or tracks a local branch.
+func (r *Repo) UpstreamRemote(branch string) *Remote {
+	if branch == "" {
+		return nil
+	}
+	entries, err := r.Config()
+	if err != nil {
+		return nil
+	}
+
+	name := ""
+	for _, e := range entries {
+		if e.Section == "branch" && e.Subsection == branch && e.Key == "remote" {
+			name = e.Value
+		}
+	}
+	if name == "" || name == "." {
+		return nil
+	}
+
+	remote := &Remote{Name: name}
+	for _, e := range entries {
+		if e.Section == "remote" && e.Subsection == name && e.Key == "url" {
+			remote.URL = e.Value
+			break
+		}
+	}
+	remote.Host, remote.Owner, remote.Repo = ParseRemoteURL(remote.URL)
+	return remote
+}
+
+// ParseRemoteURL splits a git remote URL into host, owner, and repository
+// name. It understands URL forms (https://, ssh://, git://), scp-like
+// "user@host:owner/repo.git", and local paths, for which host is empty.
+// Nested groups, as on GitLab, all end up in owner.
+func ParseRemoteURL(raw string) (host, owner, repo string) {
+	path := raw
+	switch {
+	case strings.Contains(raw, "://"):
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", "", ""
+		}
+		if u.Scheme != "file" {
+			host = u.Hostname()
+		}
+		path = u.Path
+	default:
+		// scp-like syntax has a colon before any slash.
+		if colon := strings.Index(raw, ":"); colon > 0 && !strings.Contains(raw[:colon], "/") {
+			host = raw[:colon]
+			if at := strings.LastIndex(host, "@"); at >= 0 {
+				host = host[at+1:]
+			}
+			path = raw[colon+1:]
+		}
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if slash := strings.LastIndex(path, "/"); slash >= 0 {
+		return host, path[:slash], path[slash+1:]
+	}
+	return host, "", path
+}
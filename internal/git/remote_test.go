@@ -0,0 +1,59 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		url                           string
+		wantHost, wantOwner, wantRepo string
+	}{
+		{"https://github.com/h2ik/claude-statusline.git", "github.com", "h2ik", "claude-statusline"},
+		{"git@github.com:h2ik/claude-statusline.git", "github.com", "h2ik", "claude-statusline"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/project.git", "gitlab.example.com", "group/sub", "project"},
+		{"https://user@bitbucket.org/team/repo", "bitbucket.org", "team", "repo"},
+		{"git://git.kernel.org/pub/scm/git/git.git", "git.kernel.org", "pub/scm/git", "git"},
+		{"/srv/git/project.git", "", "srv/git", "project"},
+		{"file:///srv/git/project.git", "", "srv/git", "project"},
+		{"../lib", "", "..", "lib"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			host, owner, repo := ParseRemoteURL(tt.url)
+			if host != tt.wantHost || owner != tt.wantOwner || repo != tt.wantRepo {
+				t.Errorf("got (%q, %q, %q), want (%q, %q, %q)",
+					host, owner, repo, tt.wantHost, tt.wantOwner, tt.wantRepo)
+			}
+		})
+	}
+}
+
+func TestTakeSnapshot_UpstreamRemote(t *testing.T) {
+	origin := setupGitRepo(t)
+	clone := filepath.Join(t.TempDir(), "clone")
+	mustRun(t, gitCmd(origin, "clone", "-q", origin, clone))
+	mustRun(t, gitCmd(clone, "remote", "set-url", "origin", "git@github.com:h2ik/claude-statusline.git"))
+
+	_ = os.WriteFile(filepath.Join(clone, "ahead.txt"), []byte("ahead"), 0644)
+	mustRun(t, gitCmd(clone, "add", "ahead.txt"))
+	mustRun(t, gitCmd(clone, "-c", "user.email=t@t", "-c", "user.name=T", "commit", "--no-verify", "-qm", "ahead"))
+
+	snap := TakeSnapshot(clone, Options{})
+	if snap.Upstream == "" || snap.Ahead != 1 {
+		t.Fatalf("expected to be 1 commit ahead of upstream, got %+v", snap.Status)
+	}
+	r := snap.Remote
+	if r == nil {
+		t.Fatal("expected upstream remote")
+	}
+	if r.Name != "origin" || r.Host != "github.com" || r.Owner != "h2ik" || r.Repo != "claude-statusline" {
+		t.Errorf("unexpected remote %+v", r)
+	}
+
+	if snap := TakeSnapshot(origin, Options{}); snap.Upstream != "" || snap.Remote != nil {
+		t.Errorf("expected no upstream for a repo without remotes, got %+v", snap)
+	}
+}
@@ -0,0 +1,114 @@
+package git
+
+import "errors"
+
+// Snapshot captures the repository state the statusline renders for a single
+// directory. It is gathered once per render and shared by every component
+// instead of each component re-running the same git commands.
+type Snapshot struct {
+	Dir    string
+	IsRepo bool
+	GitDir string
+	Status
+
+	// StatusErr is set when git status failed; the change counts in Status
+	// are then unknown rather than zero. It is not persisted by StateCache,
+	// which never stores a snapshot whose status failed.
+	StatusErr error `json:"-"`
+
+	// LargeRepo is set when the repository was inspected in large-repo mode,
+	// so Untracked was not counted.
+	LargeRepo bool
+
+	// Head labels a detached HEAD: the exact tag or the short commit hash.
+	// It is empty when a branch is checked out.
+	Head string
+
+	// Remote is the remote the current branch's upstream lives on, or nil
+	// when there is no upstream or it is a local branch.
+	Remote *Remote
+
+	// Operation is the rebase, merge, etc. in progress, or nil.
+	Operation *Operation
+
+	IsWorktree   bool
+	WorktreeName string
+}
+
+// Clean reports whether the working tree is known to be clean. A failed
+// status check counts as dirty, matching how repo_info has always treated it.
+func (s *Snapshot) Clean() bool {
+	return s.StatusErr == nil && s.Status.Clean()
+}
+
+// StatusTimedOut reports whether git status was killed for exceeding the
+// command timeout, leaving the working tree state unknown.
+func (s *Snapshot) StatusTimedOut() bool {
+	return errors.Is(s.StatusErr, ErrTimeout)
+}
+
+// TakeSnapshot gathers the repository state for dir. HEAD, branch, worktree
+// identity, and in-progress operations are read straight from .git; the git
+// binary runs once, for porcelain v2 status (dirty state and upstream
+// tracking), with --untracked-files=no in large-repo mode per opts. A
+// directory outside any repository yields a Snapshot with IsRepo false
+// without spawning anything.
+func TakeSnapshot(dir string, opts Options) *Snapshot {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return &Snapshot{Dir: dir}
+	}
+	return snapshotRepo(repo, dir, opts)
+}
+
+// snapshotRepo is TakeSnapshot for an already located repository.
+func snapshotRepo(repo *Repo, dir string, opts Options) *Snapshot {
+	s := &Snapshot{Dir: dir, IsRepo: true}
+	s.GitDir = repo.GitDir
+	s.IsWorktree, s.WorktreeName = repo.IsWorktree()
+	s.Operation = DetectOperation(repo.GitDir)
+
+	statusFn := GetStatus
+	if opts.IsLarge(repo) {
+		s.LargeRepo = true
+		statusFn = GetTrackedStatus
+	}
+	if status, err := statusFn(dir); err == nil {
+		s.Status = *status
+	} else {
+		s.StatusErr = err
+	}
+
+	// The reader is authoritative for HEAD even when status succeeded, so
+	// branch information survives a failed or skipped status call.
+	branch, oid, err := repo.Head()
+	if err != nil {
+		return s
+	}
+	s.Oid = oid
+	if branch != "" {
+		s.Branch = branch
+		s.Detached = false
+		if s.Upstream != "" {
+			s.Remote = repo.UpstreamRemote(branch)
+		}
+		return s
+	}
+
+	s.Branch = "HEAD"
+	s.Detached = true
+	if tag := repo.TagAt(oid); tag != "" {
+		s.Head = tag
+	} else {
+		s.Head = shortHash(oid)
+	}
+	return s
+}
+
+// shortHash abbreviates a commit hash to 7 characters.
+func shortHash(oid string) string {
+	if len(oid) > 7 {
+		return oid[:7]
+	}
+	return oid
+}
@@ -0,0 +1,258 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cache"
+)
+
+// Cache lifetimes for StateCache entries. The fingerprint already changes
+// whenever HEAD, the current branch, or the index is written, so the TTLs
+// only bound what it cannot see: edits to the working tree that have not
+// been staged (snapshots), and commits on other branches.
+const (
+	snapshotCacheTTL  = 10 * time.Second
+	commitsCacheTTL   = 10 * time.Minute
+	submoduleCacheTTL = 10 * time.Minute
+	stateCacheVersion = "v2"
+)
+
+// StateCache persists git lookups across renders in a cache.Cache, keyed by
+// repository path plus a fingerprint of the files git rewrites when the
+// repository changes. Repeated renders during a Claude turn then reuse the
+// previous snapshot, commit count, last commit, diff stat, submodule
+// status, and worktree list instead of spawning git again.
+type StateCache struct {
+	cache *cache.Cache
+}
+
+// NewStateCache creates a StateCache backed by c.
+func NewStateCache(c *cache.Cache) *StateCache {
+	return &StateCache{cache: c}
+}
+
+// Snapshot returns TakeSnapshot(dir, opts), reusing a cached snapshot of
+// the same repository while its fingerprint is unchanged. Snapshots whose
+// status call failed or timed out are returned but never cached, so the next
+// render tries again.
+func (sc *StateCache) Snapshot(dir string, opts Options) *Snapshot {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return &Snapshot{Dir: dir}
+	}
+
+	large := opts.IsLarge(repo)
+	if data, err := sc.cache.Get(snapshotKey(repo, large), snapshotCacheTTL); err == nil {
+		var s Snapshot
+		if json.Unmarshal(data, &s) == nil {
+			s.Dir = dir
+			return &s
+		}
+	}
+
+	s := snapshotRepo(repo, dir, opts)
+	if s.StatusErr == nil {
+		// Re-key after the fact: git status may have refreshed the index,
+		// and the next render will see that newer fingerprint.
+		if data, err := json.Marshal(s); err == nil {
+			_ = sc.cache.Set(snapshotKey(repo, large), data, snapshotCacheTTL)
+		}
+	}
+	return s
+}
+
+func snapshotKey(repo *Repo, large bool) string {
+	return fmt.Sprintf("git-snapshot:%s:%s:large=%t", stateCacheVersion, Fingerprint(repo), large)
+}
+
+// CountCommits returns CountCommits(dir, q), cached per repository state and
+// query. Counting across all branches also keys on the local branch refs.
+func (sc *StateCache) CountCommits(dir string, q CommitQuery) (int, error) {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	key := fmt.Sprintf("git-commits:%s:%s:%d:%q", stateCacheVersion, Fingerprint(repo), q.Since.Unix(), q.Authors)
+	if q.AllBranches {
+		key += ":branches:" + fileStamp(filepath.Join(repo.CommonDir, "refs", "heads")) +
+			":" + fileStamp(filepath.Join(repo.CommonDir, "packed-refs"))
+	}
+	if data, err := sc.cache.Get(key, commitsCacheTTL); err == nil {
+		if n, err := strconv.Atoi(string(data)); err == nil {
+			return n, nil
+		}
+	}
+
+	n, err := CountCommits(dir, q)
+	if err != nil {
+		return 0, err
+	}
+	_ = sc.cache.Set(key, []byte(strconv.Itoa(n)), commitsCacheTTL)
+	return n, nil
+}
+
+// LastCommit returns GetLastCommit(dir), cached per repository state and
+// tag refs so a new tag updates the describe output.
+func (sc *StateCache) LastCommit(dir string) (*Commit, error) {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("git-last-commit:%s:%s:%s:%s", stateCacheVersion, Fingerprint(repo),
+		fileStamp(filepath.Join(repo.CommonDir, "refs", "tags")),
+		fileStamp(filepath.Join(repo.CommonDir, "packed-refs")))
+	if data, err := sc.cache.Get(key, commitsCacheTTL); err == nil {
+		var c Commit
+		if json.Unmarshal(data, &c) == nil {
+			return &c, nil
+		}
+	}
+
+	c, err := GetLastCommit(dir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(c); err == nil {
+		_ = sc.cache.Set(key, data, commitsCacheTTL)
+	}
+	return c, nil
+}
+
+// DiffStat returns GetDiffStat(dir), cached per repository state. Like the
+// snapshot, it cannot see unstaged edits, so it shares the snapshot TTL.
+func (sc *StateCache) DiffStat(dir string) (*DiffStat, error) {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("git-diff-stat:%s:%s", stateCacheVersion, Fingerprint(repo))
+	if data, err := sc.cache.Get(key, snapshotCacheTTL); err == nil {
+		var d DiffStat
+		if json.Unmarshal(data, &d) == nil {
+			return &d, nil
+		}
+	}
+
+	d, err := GetDiffStat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(d); err == nil {
+		_ = sc.cache.Set(key, data, snapshotCacheTTL)
+	}
+	return d, nil
+}
+
+// SubmoduleStatus returns GetSubmoduleStatus(dir), cached per repository
+// state, .gitmodules revision, and the checked-out commit of each submodule.
+func (sc *StateCache) SubmoduleStatus(dir string) (*SubmoduleStatus, error) {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return &SubmoduleStatus{}, nil
+	}
+
+	key := fmt.Sprintf("git-submodules:%s:%s:%s", stateCacheVersion, Fingerprint(repo), submoduleStamps(repo))
+	if data, err := sc.cache.Get(key, submoduleCacheTTL); err == nil {
+		var st SubmoduleStatus
+		if json.Unmarshal(data, &st) == nil {
+			return &st, nil
+		}
+	}
+
+	st, err := GetSubmoduleStatus(dir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(st); err == nil {
+		_ = sc.cache.Set(key, data, submoduleCacheTTL)
+	}
+	return st, nil
+}
+
+// Worktrees returns ListWorktrees(dir), cached per repository and the
+// HEAD, index, and lock state of every worktree. Because edits inside other
+// worktrees are not visible to the key, entries share the snapshot TTL.
+func (sc *StateCache) Worktrees(dir string) ([]Worktree, error) {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("git-worktrees:%s:%s:%s", stateCacheVersion, repo.WorkTree, worktreeStamps(repo))
+	if data, err := sc.cache.Get(key, snapshotCacheTTL); err == nil {
+		var wts []Worktree
+		if json.Unmarshal(data, &wts) == nil {
+			return wts, nil
+		}
+	}
+
+	wts, err := ListWorktrees(dir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(wts); err == nil {
+		_ = sc.cache.Set(key, data, snapshotCacheTTL)
+	}
+	return wts, nil
+}
+
+// worktreeStamps fingerprints the administrative files of the main worktree
+// and every linked worktree under CommonDir/worktrees.
+func worktreeStamps(r *Repo) string {
+	dirs := []string{r.CommonDir}
+	adminDir := filepath.Join(r.CommonDir, "worktrees")
+	if entries, err := os.ReadDir(adminDir); err == nil {
+		for _, e := range entries {
+			dirs = append(dirs, filepath.Join(adminDir, e.Name()))
+		}
+	}
+
+	parts := []string{fileStamp(adminDir)}
+	for _, d := range dirs {
+		parts = append(parts,
+			fileStamp(filepath.Join(d, "HEAD")),
+			fileStamp(filepath.Join(d, "index")),
+			fileStamp(filepath.Join(d, "locked")),
+		)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Fingerprint identifies the current state of r: its work tree path plus the
+// size and modification time of HEAD, the current branch's ref file, and the
+// index. Commits, checkouts, staging, and resets all rewrite at least one of
+// them. FETCH_HEAD is included too, since a fetch moves the upstream that
+// ahead/behind counts are measured against.
+func Fingerprint(r *Repo) string {
+	parts := []string{r.WorkTree}
+	for _, path := range []string{
+		filepath.Join(r.GitDir, "HEAD"),
+		r.RefFile(),
+		filepath.Join(r.GitDir, "index"),
+		filepath.Join(r.CommonDir, "FETCH_HEAD"),
+	} {
+		parts = append(parts, fileStamp(path))
+	}
+	return strings.Join(parts, ":")
+}
+
+// fileStamp returns "<size>@<mtime ns>" for path, or "-" when it is missing.
+func fileStamp(path string) string {
+	if path == "" {
+		return "-"
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d@%d", info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,113 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cache"
+)
+
+func TestRepo_RefFile(t *testing.T) {
+	dir := setupGitRepo(t)
+	repo, err := FindRepo(dir)
+	if err != nil {
+		t.Fatalf("FindRepo failed: %v", err)
+	}
+	branch, oid, _ := repo.Head()
+
+	if got, want := repo.RefFile(), filepath.Join(repo.GitDir, "refs", "heads", branch); got != want {
+		t.Errorf("loose ref: got %q, want %q", got, want)
+	}
+
+	mustRun(t, gitCmd(dir, "pack-refs", "--all"))
+	if got, want := repo.RefFile(), filepath.Join(repo.CommonDir, "packed-refs"); got != want {
+		t.Errorf("packed ref: got %q, want %q", got, want)
+	}
+
+	mustRun(t, gitCmd(dir, "checkout", "-q", "--detach", oid))
+	if got := repo.RefFile(); got != "" {
+		t.Errorf("detached HEAD: expected no ref file, got %q", got)
+	}
+}
+
+func TestFingerprint_ChangesOnCommitAndStage(t *testing.T) {
+	dir := setupGitRepo(t)
+	repo, _ := FindRepo(dir)
+
+	before := Fingerprint(repo)
+	if again := Fingerprint(repo); again != before {
+		t.Fatalf("fingerprint not stable: %q vs %q", before, again)
+	}
+
+	_ = os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644)
+	mustRun(t, gitCmd(dir, "add", "new.txt"))
+	staged := Fingerprint(repo)
+	if staged == before {
+		t.Error("expected staging to change the fingerprint")
+	}
+
+	mustRun(t, gitCmd(dir, "commit", "--no-verify", "-qm", "second"))
+	if Fingerprint(repo) == staged {
+		t.Error("expected a commit to change the fingerprint")
+	}
+}
+
+func TestStateCache_ReusesSnapshotUntilIndexChanges(t *testing.T) {
+	dir := setupGitRepo(t)
+	sc := NewStateCache(cache.New(t.TempDir()))
+
+	if snap := sc.Snapshot(dir, Options{}); !snap.IsRepo || !snap.Clean() {
+		t.Fatalf("expected clean repo snapshot, got %+v", snap)
+	}
+
+	// A working-tree edit leaves the fingerprint alone, so the cached clean
+	// snapshot is served.
+	_ = os.WriteFile(filepath.Join(dir, "test.txt"), []byte("modified"), 0644)
+	if snap := sc.Snapshot(dir, Options{}); !snap.Clean() {
+		t.Errorf("expected cached clean snapshot, got %+v", snap.Status)
+	}
+
+	mustRun(t, gitCmd(dir, "add", "test.txt"))
+	if snap := sc.Snapshot(dir, Options{}); snap.Staged != 1 {
+		t.Errorf("expected fresh snapshot with 1 staged change, got %+v", snap.Status)
+	}
+
+	if snap := sc.Snapshot(t.TempDir(), Options{}); snap.IsRepo {
+		t.Error("expected no repo outside a repository")
+	}
+}
+
+func TestStateCache_CommitsAndSubmodules(t *testing.T) {
+	dir := setupGitRepo(t)
+	sc := NewStateCache(cache.New(t.TempDir()))
+
+	today := CommitQuery{Since: StartOfDay(time.Now())}
+	if n, err := sc.CountCommits(dir, today); err != nil || n != 1 {
+		t.Fatalf("expected 1 commit today, got %d (%v)", n, err)
+	}
+	_ = os.WriteFile(filepath.Join(dir, "second.txt"), []byte("2"), 0644)
+	mustRun(t, gitCmd(dir, "add", "second.txt"))
+	mustRun(t, gitCmd(dir, "commit", "--no-verify", "-qm", "second"))
+	if n, _ := sc.CountCommits(dir, today); n != 2 {
+		t.Errorf("expected new commit to invalidate the count, got %d", n)
+	}
+
+	if st, err := sc.SubmoduleStatus(dir); err != nil || st.Total != 0 {
+		t.Fatalf("expected no submodules, got %+v (%v)", st, err)
+	}
+	sub := addSubmodule(t, dir, "lib")
+	if st, _ := sc.SubmoduleStatus(dir); st.Total != 1 || st.InSync() != 1 {
+		t.Fatalf("expected adding a submodule to invalidate the status, got %+v", st)
+	}
+
+	// Moving the submodule's HEAD touches nothing in the superproject, but
+	// must still invalidate the cached status.
+	_ = os.WriteFile(filepath.Join(sub, "moved.txt"), []byte("moved"), 0644)
+	mustRun(t, gitCmd(sub, "add", "moved.txt"))
+	mustRun(t, gitCmd(sub, "-c", "user.email=t@t", "-c", "user.name=T", "commit", "--no-verify", "-qm", "moved"))
+	if st, _ := sc.SubmoduleStatus(dir); st.Modified != 1 {
+		t.Errorf("expected a moved submodule to invalidate the status, got %+v", st)
+	}
+}
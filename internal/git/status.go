@@ -0,0 +1,117 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Status is the parsed output of a single
+// `git status --porcelain=v2 --branch --show-stash` call: branch and upstream
+// tracking information plus counts of changed paths by kind.
+type Status struct {
+	Oid      string // HEAD commit hash, or "(initial)" before the first commit
+	Branch   string // current branch, or "HEAD" when Detached
+	Detached bool
+	Upstream string // e.g. "origin/main"; empty when no upstream is set
+	Ahead    int
+	Behind   int
+
+	Staged     int // paths with changes in the index
+	Unstaged   int // tracked paths with changes in the working tree
+	Untracked  int
+	Conflicted int
+	Stashes    int
+}
+
+// Clean reports whether the working tree and index have no changes at all,
+// including untracked files.
+func (s *Status) Clean() bool {
+	return s.Staged == 0 && s.Unstaged == 0 && s.Untracked == 0 && s.Conflicted == 0
+}
+
+// GetStatus runs git status once for the repo at dir and parses the result.
+// --show-stash needs git 2.35+; older versions are retried without it.
+func GetStatus(dir string) (*Status, error) {
+	return getStatus(dir)
+}
+
+// GetTrackedStatus is GetStatus without the untracked-file scan
+// (--untracked-files=no), for repositories where walking the working tree
+// for new files is too slow. Untracked is always zero in the result.
+func GetTrackedStatus(dir string) (*Status, error) {
+	return getStatus(dir, "--untracked-files=no")
+}
+
+func getStatus(dir string, extra ...string) (*Status, error) {
+	output, err := runStatus(dir, append(extra, "--show-stash")...)
+	if err != nil && strings.Contains(err.Error(), "show-stash") {
+		output, err = runStatus(dir, extra...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseStatus(output), nil
+}
+
+func runStatus(dir string, extra ...string) ([]byte, error) {
+	args := append([]string{"status", "--porcelain=v2", "--branch"}, extra...)
+	return run(dir, args...)
+}
+
+// parseStatus parses porcelain v2 output. Unknown or malformed lines are
+// ignored so newer git versions adding fields don't break parsing.
+func parseStatus(output []byte) *Status {
+	s := &Status{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 2 {
+			continue
+		}
+		switch line[0] {
+		case '#':
+			parseStatusHeader(s, line)
+		case '1', '2':
+			// "1 XY ..." / "2 XY ...": X is the index, Y the working tree;
+			// '.' means unchanged on that side.
+			if len(line) >= 4 {
+				if line[2] != '.' {
+					s.Staged++
+				}
+				if line[3] != '.' {
+					s.Unstaged++
+				}
+			}
+		case 'u':
+			s.Conflicted++
+		case '?':
+			s.Untracked++
+		}
+	}
+	return s
+}
+
+func parseStatusHeader(s *Status, line string) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return
+	}
+	switch fields[1] {
+	case "branch.oid":
+		s.Oid = fields[2]
+	case "branch.head":
+		if fields[2] == "(detached)" {
+			s.Detached = true
+			s.Branch = "HEAD"
+		} else {
+			s.Branch = fields[2]
+		}
+	case "branch.upstream":
+		s.Upstream = fields[2]
+	case "branch.ab":
+		if len(fields) >= 4 {
+			s.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+			s.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+		}
+	case "stash":
+		s.Stashes, _ = strconv.Atoi(fields[2])
+	}
+}
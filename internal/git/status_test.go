@@ -0,0 +1,132 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/command"
+)
+
+func TestParseStatus(t *testing.T) {
+	output := []byte(`# branch.oid 1234567890abcdef1234567890abcdef12345678
+# branch.head feature/x
+# branch.upstream origin/feature/x
+# branch.ab +2 -1
+# stash 3
+1 M. N... 100644 100644 100644 aaa bbb staged.go
+1 .M N... 100644 100644 100644 aaa bbb unstaged.go
+1 MM N... 100644 100644 100644 aaa bbb both.go
+2 R. N... 100644 100644 100644 aaa bbb R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 aaa bbb ccc conflict.go
+? untracked.txt
+? other.txt
+! ignored.log
+`)
+
+	s := parseStatus(output)
+
+	if s.Oid != "1234567890abcdef1234567890abcdef12345678" {
+		t.Errorf("oid: got %q", s.Oid)
+	}
+	if s.Branch != "feature/x" || s.Detached {
+		t.Errorf("branch: got %q (detached=%v)", s.Branch, s.Detached)
+	}
+	if s.Upstream != "origin/feature/x" {
+		t.Errorf("upstream: got %q", s.Upstream)
+	}
+	if s.Ahead != 2 || s.Behind != 1 {
+		t.Errorf("ahead/behind: got +%d -%d, want +2 -1", s.Ahead, s.Behind)
+	}
+	if s.Stashes != 3 {
+		t.Errorf("stashes: got %d, want 3", s.Stashes)
+	}
+	if s.Staged != 3 {
+		t.Errorf("staged: got %d, want 3", s.Staged)
+	}
+	if s.Unstaged != 2 {
+		t.Errorf("unstaged: got %d, want 2", s.Unstaged)
+	}
+	if s.Conflicted != 1 {
+		t.Errorf("conflicted: got %d, want 1", s.Conflicted)
+	}
+	if s.Untracked != 2 {
+		t.Errorf("untracked: got %d, want 2", s.Untracked)
+	}
+	if s.Clean() {
+		t.Error("expected dirty status")
+	}
+}
+
+func TestParseStatus_DetachedNoUpstream(t *testing.T) {
+	s := parseStatus([]byte("# branch.oid abc123\n# branch.head (detached)\n"))
+
+	if !s.Detached || s.Branch != "HEAD" {
+		t.Errorf("expected detached HEAD, got %q (detached=%v)", s.Branch, s.Detached)
+	}
+	if s.Upstream != "" || s.Ahead != 0 || s.Behind != 0 {
+		t.Errorf("expected no upstream info, got %+v", s)
+	}
+	if !s.Clean() {
+		t.Error("expected clean status")
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	_ = os.WriteFile(filepath.Join(dir, "test.txt"), []byte("modified"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "staged.txt"), []byte("new"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("new"), 0644)
+	if err := gitCmd(dir, "add", "staged.txt").Run(); err != nil {
+		t.Fatalf("git add failed: %v", err)
+	}
+
+	s, err := GetStatus(dir)
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if s.Staged != 1 || s.Unstaged != 1 || s.Untracked != 1 {
+		t.Errorf("expected 1 staged, 1 unstaged, 1 untracked; got %+v", s)
+	}
+
+	if _, err := GetStatus(t.TempDir()); err == nil {
+		t.Error("expected error outside a repository")
+	}
+}
+
+func TestGetTrackedStatus_SkipsUntracked(t *testing.T) {
+	dir := setupGitRepo(t)
+	_ = os.WriteFile(filepath.Join(dir, "test.txt"), []byte("modified"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("new"), 0644)
+
+	s, err := GetTrackedStatus(dir)
+	if err != nil {
+		t.Fatalf("GetTrackedStatus failed: %v", err)
+	}
+	if s.Unstaged != 1 || s.Untracked != 0 {
+		t.Errorf("expected 1 unstaged and no untracked, got %+v", s)
+	}
+}
+
+func TestGetStatus_Timeout(t *testing.T) {
+	dir := setupGitRepo(t)
+
+	command.SetTimeout(time.Nanosecond)
+	t.Cleanup(func() { command.SetTimeout(command.DefaultTimeout) })
+
+	_, err := GetStatus(dir)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+
+	snap := TakeSnapshot(dir, Options{})
+	if !snap.IsRepo || !snap.StatusTimedOut() {
+		t.Errorf("expected a repo snapshot with timed-out status, got %+v", snap)
+	}
+	if snap.Branch == "" {
+		t.Error("expected branch to be read from .git despite the timeout")
+	}
+}
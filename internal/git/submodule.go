@@ -0,0 +1,73 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// SubmoduleStatus summarizes `git submodule status` by the state character
+// git prints before each submodule.
+type SubmoduleStatus struct {
+	Total         int
+	Uninitialized int // '-': not checked out
+	Modified      int // '+': checked-out commit differs from the one recorded
+	Conflicted    int // 'U': merge conflicts in the gitlink
+}
+
+// InSync returns the number of submodules checked out at their recorded commit.
+func (s *SubmoduleStatus) InSync() int {
+	return s.Total - s.Uninitialized - s.Modified - s.Conflicted
+}
+
+// GetSubmoduleStatus runs `git submodule status` for the repo at dir. A repo
+// whose .gitmodules declares nothing yields a zero status without running git.
+func GetSubmoduleStatus(dir string) (*SubmoduleStatus, error) {
+	if count, _ := GetSubmoduleCount(dir); count == 0 {
+		return &SubmoduleStatus{}, nil
+	}
+	output, err := run(dir, "submodule", "status")
+	if err != nil {
+		return nil, err
+	}
+	return parseSubmoduleStatus(output), nil
+}
+
+// parseSubmoduleStatus parses lines of the form "<c><sha> <path> (<describe>)"
+// where c is ' ', '-', '+', or 'U'.
+func parseSubmoduleStatus(output []byte) *SubmoduleStatus {
+	s := &SubmoduleStatus{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		s.Total++
+		switch line[0] {
+		case '-':
+			s.Uninitialized++
+		case '+':
+			s.Modified++
+		case 'U':
+			s.Conflicted++
+		}
+	}
+	return s
+}
+
+// submoduleStamps fingerprints the checked-out commit of every initialized
+// submodule of r, so cached submodule status is invalidated when one of them
+// moves. Uninitialized submodules contribute a placeholder.
+func submoduleStamps(r *Repo) string {
+	subs, _ := r.Submodules()
+	parts := []string{fileStamp(filepath.Join(r.WorkTree, ".gitmodules"))}
+	for _, sub := range subs {
+		path := filepath.Join(r.WorkTree, filepath.FromSlash(sub.Path))
+		sr, err := FindRepo(path)
+		if err != nil || sr.WorkTree != path {
+			// Not checked out: FindRepo found nothing, or the superproject.
+			parts = append(parts, "-")
+			continue
+		}
+		parts = append(parts, fileStamp(filepath.Join(sr.GitDir, "HEAD"))+"/"+fileStamp(sr.RefFile()))
+	}
+	return strings.Join(parts, ",")
+}
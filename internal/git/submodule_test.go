@@ -0,0 +1,60 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// addSubmodule creates a new repository and adds it to dir as a submodule at
+// path, committing the result. It returns the submodule's checkout.
+func addSubmodule(t *testing.T, dir, path string) string {
+	t.Helper()
+	src := setupGitRepo(t)
+	mustRun(t, gitCmd(dir, "-c", "protocol.file.allow=always", "submodule", "add", "-q", src, path))
+	mustRun(t, gitCmd(dir, "commit", "--no-verify", "-qm", "add "+path))
+	return filepath.Join(dir, path)
+}
+
+func TestParseSubmoduleStatus(t *testing.T) {
+	output := []byte(` 1234567890abcdef1234567890abcdef12345678 lib/ok (heads/main)
+-1234567890abcdef1234567890abcdef12345678 lib/uninit
++1234567890abcdef1234567890abcdef12345678 lib/moved (v1.0-2-gabcdef0)
+U0000000000000000000000000000000000000000 lib/conflict
+`)
+	s := parseSubmoduleStatus(output)
+	if s.Total != 4 || s.Uninitialized != 1 || s.Modified != 1 || s.Conflicted != 1 || s.InSync() != 1 {
+		t.Errorf("unexpected status %+v", s)
+	}
+}
+
+func TestGetSubmoduleStatus(t *testing.T) {
+	dir := setupGitRepo(t)
+	if s, err := GetSubmoduleStatus(dir); err != nil || s.Total != 0 {
+		t.Fatalf("expected no submodules, got %+v (%v)", s, err)
+	}
+
+	one := addSubmodule(t, dir, "one")
+	addSubmodule(t, dir, "two")
+
+	s, err := GetSubmoduleStatus(dir)
+	if err != nil {
+		t.Fatalf("GetSubmoduleStatus failed: %v", err)
+	}
+	if s.Total != 2 || s.InSync() != 2 {
+		t.Errorf("expected 2 in-sync submodules, got %+v", s)
+	}
+
+	_ = os.WriteFile(filepath.Join(one, "moved.txt"), []byte("moved"), 0644)
+	mustRun(t, gitCmd(one, "add", "moved.txt"))
+	mustRun(t, gitCmd(one, "-c", "user.email=t@t", "-c", "user.name=T", "commit", "--no-verify", "-qm", "moved"))
+	mustRun(t, gitCmd(dir, "submodule", "deinit", "-q", "-f", "two"))
+
+	s, err = GetSubmoduleStatus(dir)
+	if err != nil {
+		t.Fatalf("GetSubmoduleStatus failed: %v", err)
+	}
+	if s.Modified != 1 || s.Uninitialized != 1 || s.InSync() != 0 {
+		t.Errorf("expected one moved and one uninitialized submodule, got %+v", s)
+	}
+}
@@ -0,0 +1,111 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Worktree is one entry from `git worktree list --porcelain`, plus whether
+// its working tree has changes.
+type Worktree struct {
+	Path     string
+	Head     string // commit hash checked out
+	Branch   string // short branch name; empty when Detached or Bare
+	Bare     bool
+	Detached bool
+	Locked   bool
+	Prunable bool // its directory is gone; `git worktree prune` would remove it
+	Dirty    bool
+	Current  bool // the worktree containing the directory that was listed
+}
+
+// worktreeStatusWorkers bounds how many worktree status checks run at once,
+// so a repository with dozens of worktrees does not fork dozens of gits.
+const worktreeStatusWorkers = 4
+
+// ListWorktrees lists every worktree of the repository containing dir, the
+// main one first, and checks each reachable one for changes to tracked
+// files. Up to worktreeStatusWorkers checks run concurrently; a worktree
+// whose status cannot be read is reported clean.
+func ListWorktrees(dir string) ([]Worktree, error) {
+	repo, err := FindRepo(dir)
+	if err != nil {
+		return nil, err
+	}
+	output, err := run(dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	wts := parseWorktrees(output)
+
+	current := realPath(repo.WorkTree)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, worktreeStatusWorkers)
+	for i := range wts {
+		wt := &wts[i]
+		wt.Current = realPath(wt.Path) == current
+		if wt.Bare || wt.Prunable {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			wt.Dirty = worktreeDirty(wt.Path)
+		}()
+	}
+	wg.Wait()
+	return wts, nil
+}
+
+// worktreeDirty reports whether the checkout at path has changes to tracked
+// files. Untracked files are not scanned for: across many worktrees that is
+// the slow part, and it is what large-repo mode skips for the current one.
+func worktreeDirty(path string) bool {
+	s, err := GetTrackedStatus(path)
+	return err == nil && !s.Clean()
+}
+
+// parseWorktrees parses porcelain output: blank-line separated records of
+// "worktree <path>", "HEAD <oid>", "branch <ref>", and the bare, detached,
+// locked [reason], and prunable [reason] attributes.
+func parseWorktrees(output []byte) []Worktree {
+	var wts []Worktree
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			wts = append(wts, Worktree{Path: value})
+			continue
+		}
+		if len(wts) == 0 {
+			continue
+		}
+		wt := &wts[len(wts)-1]
+		switch key {
+		case "HEAD":
+			wt.Head = value
+		case "branch":
+			wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			wt.Bare = true
+		case "detached":
+			wt.Detached = true
+		case "locked":
+			wt.Locked = true
+		case "prunable":
+			wt.Prunable = true
+		}
+	}
+	return wts
+}
+
+// realPath resolves symlinks so paths reported by git compare equal to ones
+// found by walking the filesystem; it returns path unchanged on error.
+func realPath(path string) string {
+	if p, err := filepath.EvalSymlinks(path); err == nil {
+		return p
+	}
+	return path
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	output := []byte(`worktree /src/repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /src/repo-feature
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/x
+
+worktree /src/repo-detached
+HEAD 3333333333333333333333333333333333333333
+detached
+locked on usb disk
+
+worktree /src/repo-gone
+HEAD 4444444444444444444444444444444444444444
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+
+`)
+	wts := parseWorktrees(output)
+	if len(wts) != 4 {
+		t.Fatalf("expected 4 worktrees, got %d: %+v", len(wts), wts)
+	}
+	if wts[0].Path != "/src/repo" || wts[0].Branch != "main" {
+		t.Errorf("unexpected main worktree %+v", wts[0])
+	}
+	if wts[1].Branch != "feature/x" {
+		t.Errorf("expected branch feature/x, got %q", wts[1].Branch)
+	}
+	if !wts[2].Detached || !wts[2].Locked || wts[2].Branch != "" {
+		t.Errorf("expected detached locked worktree, got %+v", wts[2])
+	}
+	if !wts[3].Prunable {
+		t.Errorf("expected prunable worktree, got %+v", wts[3])
+	}
+}
+
+func TestListWorktrees(t *testing.T) {
+	dir := setupGitRepo(t)
+	parent := t.TempDir()
+	feature := filepath.Join(parent, "feature")
+	gone := filepath.Join(parent, "gone")
+	mustRun(t, gitCmd(dir, "worktree", "add", "-q", "-b", "feature", feature))
+	mustRun(t, gitCmd(dir, "worktree", "add", "-q", "-b", "gone", gone))
+	mustRun(t, gitCmd(dir, "worktree", "lock", feature))
+	_ = os.RemoveAll(gone)
+	_ = os.WriteFile(filepath.Join(feature, "test.txt"), []byte("modified"), 0644)
+	// Untracked files do not make a worktree dirty.
+	_ = os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new"), 0644)
+
+	wts, err := ListWorktrees(feature)
+	if err != nil {
+		t.Fatalf("ListWorktrees failed: %v", err)
+	}
+	if len(wts) != 3 {
+		t.Fatalf("expected 3 worktrees, got %+v", wts)
+	}
+	main, feat, prunable := wts[0], wts[1], wts[2]
+	if main.Current || main.Dirty || main.Branch == "" {
+		t.Errorf("unexpected main worktree %+v", main)
+	}
+	if !feat.Current || !feat.Dirty || !feat.Locked || feat.Branch != "feature" {
+		t.Errorf("expected current, dirty, locked feature worktree, got %+v", feat)
+	}
+	if !prunable.Prunable || prunable.Dirty {
+		t.Errorf("expected prunable worktree, got %+v", prunable)
+	}
+}
+
+func TestListWorktrees_MoreWorktreesThanWorkers(t *testing.T) {
+	dir := setupGitRepo(t)
+	parent := t.TempDir()
+	n := worktreeStatusWorkers*2 + 1
+	for i := 0; i < n; i++ {
+		path := filepath.Join(parent, fmt.Sprintf("wt%d", i))
+		mustRun(t, gitCmd(dir, "worktree", "add", "-q", "-b", fmt.Sprintf("wt%d", i), path))
+		if i%2 == 0 {
+			_ = os.WriteFile(filepath.Join(path, "test.txt"), []byte("modified"), 0644)
+		}
+	}
+
+	wts, err := ListWorktrees(dir)
+	if err != nil {
+		t.Fatalf("ListWorktrees failed: %v", err)
+	}
+	if len(wts) != n+1 {
+		t.Fatalf("expected %d worktrees, got %d", n+1, len(wts))
+	}
+	for i, wt := range wts[1:] {
+		if want := i%2 == 0; wt.Dirty != want {
+			t.Errorf("worktree %s: expected dirty=%v, got %v", wt.Path, want, wt.Dirty)
+		}
+	}
+}
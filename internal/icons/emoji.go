@@ -24,6 +24,16 @@ var emojiMap = map[string]string{
 	Book:       "\xf0\x9f\x93\x9a", // 📚
 	Graduation: "\xf0\x9f\x8e\x93", // 🎓
 	Sparkles:   "\xe2\x9c\xa8", // ✨
+	GitHub:     "🐙",
+	GitLab:     "🦊",
+	Bitbucket:  "🪣",
+	GitServer:  "🌐",
+	Worktree:   "🌳",
+	Commit:     "📌",
+	Tag:        "🏷️",
+	Diff:       "📝",
+	Money:      "💰",
+	Forecast:   "🔮",
 }
 
 // Get returns the emoji character for the given icon name.
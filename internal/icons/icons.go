@@ -21,6 +21,16 @@ const (
 	Book       = "book"
 	Graduation = "graduation"
 	Sparkles   = "sparkles"
+	GitHub     = "github"
+	GitLab     = "gitlab"
+	Bitbucket  = "bitbucket"
+	GitServer  = "git_server"
+	Worktree   = "worktree"
+	Commit     = "commit"
+	Tag        = "tag"
+	Diff       = "diff"
+	Money      = "money"
+	Forecast   = "forecast"
 )
 
 // AllIcons lists every known icon name for testing and validation.
@@ -28,6 +38,8 @@ var AllIcons = []string{
 	Brain, Fire, FloppyDisk, Warning, ChartUp, ChartBar, Calendar,
 	Hourglass, Pencil, Lightning, Music, Robot, CheckMark, Folder,
 	Link, Clock, Book, Graduation, Sparkles,
+	GitHub, GitLab, Bitbucket, GitServer, Worktree, Commit, Tag,
+	Diff, Money, Forecast,
 }
 
 // IconSet provides icon glyphs by name. Two implementations exist:
@@ -24,6 +24,16 @@ var nerdFontMap = map[string]string{
 	Book:       "\uf02d",    // nf-fa-book
 	Graduation: "\U000F0474", // nf-md-school
 	Sparkles:   "\U000F0674", // nf-md-creation
+	GitHub:     "\uf09b",     // nf-fa-github
+	GitLab:     "\uf296",     // nf-fa-gitlab
+	Bitbucket:  "\uf171",     // nf-fa-bitbucket
+	GitServer:  "\ue702",     // nf-dev-git
+	Worktree:   "\uf1bb",     // nf-fa-tree
+	Commit:     "\uf417",     // nf-oct-git_commit
+	Tag:        "\uf02b",     // nf-fa-tag
+	Diff:       "\uf440",     // nf-oct-diff
+	Money:      "\uf0d6",     // nf-fa-money
+	Forecast:   "\uf201",     // nf-fa-line_chart
 }
 
 // Get returns the Nerd Font glyph for the given icon name.
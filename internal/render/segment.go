@@ -1,6 +1,10 @@
 package render
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // SegmentCategory defines the background and foreground colors for a powerline segment.
 type SegmentCategory struct {
@@ -9,20 +13,24 @@ type SegmentCategory struct {
 }
 
 // componentGroup maps a component name to its semantic group.
-// Returns "dim" for unknown components.
+// Returns "dim" for unknown components, except that names starting with
+// "cost_" (such as custom cost_period instances) are cost.
 func componentGroup(name string) string {
 	switch name {
-	case "repo_info", "model_info", "bedrock_model":
+	case "repo_info", "git_remote", "worktrees", "model_info", "bedrock_model":
 		return "info"
-	case "cost_monthly", "cost_weekly", "cost_daily", "cost_live", "burn_rate":
+	case "cost_monthly", "cost_weekly", "cost_daily", "cost_project", "cost_models", "budget", "cost_forecast", "cost_live", "burn_rate":
 		return "cost"
 	case "context_window", "cache_efficiency", "block_projection":
 		return "metrics"
-	case "code_productivity", "commits":
+	case "code_productivity", "commits", "last_commit", "diff_stat":
 		return "activity"
 	case "version_info", "session_mode":
 		return "meta"
 	default:
+		if strings.HasPrefix(name, "cost_") {
+			return "cost"
+		}
 		return "dim"
 	}
 }
@@ -41,7 +41,7 @@ func TestSegmentCategory_InfoGroupIsBlue(t *testing.T) {
 }
 
 func TestSegmentCategory_CostGroupIsPeach(t *testing.T) {
-	for _, name := range []string{"cost_monthly", "cost_weekly", "cost_daily", "cost_live", "burn_rate"} {
+	for _, name := range []string{"cost_monthly", "cost_weekly", "cost_daily", "cost_live", "burn_rate", "cost_forecast", "cost_billing"} {
 		cat := SegmentCategoryFor(name, &ThemeMocha)
 		if cat.Background != ThemeMocha.Peach {
 			t.Errorf("component %q should have peach background, got %v", name, cat.Background)
@@ -0,0 +1,270 @@
+// Package report implements the `claude-statusline report` subcommand: a
+// table, CSV, or JSON breakdown of transcript cost and tokens per day, week,
+// or month, optionally grouped by project, model, or git branch.
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cost"
+)
+
+// dateLayout is the format of --since and --until.
+const dateLayout = "2006-01-02"
+
+// Options is a parsed report command line.
+type Options struct {
+	Query  cost.ReportQuery
+	Format string // "table", "csv", or "json"
+}
+
+// ParseArgs parses the report flags at now. --since defaults to the start of
+// the period 29 days, 11 weeks, or 11 months before now (30 rows' worth of
+// days, 12 of weeks or months) and --until, which is inclusive, to today.
+func ParseArgs(args []string, now time.Time, stderr io.Writer) (Options, error) {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: claude-statusline report [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Prints the cost and tokens of Claude Code transcripts per period.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	period := fs.String("period", "day", "row period: day, week, or month")
+	group := fs.String("group", "", "split rows by project, model, or branch")
+	since := fs.String("since", "", "first day to include, as YYYY-MM-DD (default: 30 days, 12 weeks, or 12 months back)")
+	until := fs.String("until", "", "last day to include, as YYYY-MM-DD (default: today)")
+	format := fs.String("format", "table", "output format: table, csv, or json")
+	if err := fs.Parse(args); err != nil {
+		return Options{}, err
+	}
+	if fs.NArg() > 0 {
+		return Options{}, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	opts := Options{Format: *format}
+	switch p := cost.Period(*period); p {
+	case cost.Day, cost.Week, cost.Month:
+		opts.Query.Period = p
+	default:
+		return Options{}, fmt.Errorf("unknown period %q (want day, week, or month)", *period)
+	}
+	switch g := cost.Grouping(*group); g {
+	case cost.ByNone, cost.ByProject, cost.ByModel, cost.ByBranch:
+		opts.Query.GroupBy = g
+	default:
+		return Options{}, fmt.Errorf("unknown group %q (want project, model, or branch)", *group)
+	}
+	switch opts.Format {
+	case "table", "csv", "json":
+	default:
+		return Options{}, fmt.Errorf("unknown format %q (want table, csv, or json)", opts.Format)
+	}
+
+	today := cost.Day.Start(now)
+	opts.Query.Until = today.AddDate(0, 0, 1)
+	if *until != "" {
+		day, err := time.ParseInLocation(dateLayout, *until, now.Location())
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid --until %q: want YYYY-MM-DD", *until)
+		}
+		opts.Query.Until = day.AddDate(0, 0, 1)
+	}
+	switch opts.Query.Period {
+	case cost.Week:
+		opts.Query.Since = cost.Week.Start(today).AddDate(0, 0, -7*11)
+	case cost.Month:
+		opts.Query.Since = cost.Month.Start(today).AddDate(0, -11, 0)
+	default:
+		opts.Query.Since = today.AddDate(0, 0, -29)
+	}
+	if *since != "" {
+		day, err := time.ParseInLocation(dateLayout, *since, now.Location())
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid --since %q: want YYYY-MM-DD", *since)
+		}
+		opts.Query.Since = day
+	}
+	if !opts.Query.Since.Before(opts.Query.Until) {
+		return Options{}, errors.New("--since must not be after --until")
+	}
+	return opts, nil
+}
+
+// Run runs the report command with args, printing the report for the
+// transcripts s reads to stdout and any usage errors to stderr. It returns
+// the process exit code.
+func Run(args []string, s *cost.TranscriptScanner, stdout, stderr io.Writer) int {
+	opts, err := ParseArgs(args, time.Now(), stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "report: %v\n", err)
+		return 2
+	}
+	if err := Write(stdout, opts, s.Report(opts.Query)); err != nil {
+		fmt.Fprintf(stderr, "report: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Write prints rows in opts.Format. Tables and JSON include a total.
+func Write(w io.Writer, opts Options, rows []cost.ReportRow) error {
+	switch opts.Format {
+	case "csv":
+		return writeCSV(w, opts, rows)
+	case "json":
+		return writeJSON(w, opts, rows)
+	default:
+		return writeTable(w, opts, rows)
+	}
+}
+
+func writeTable(w io.Writer, opts Options, rows []cost.ReportRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	group := opts.Query.GroupBy != cost.ByNone
+	line := func(period, grp string, u cost.Usage, usd string) {
+		fmt.Fprintf(tw, "%s\t", period)
+		if group {
+			fmt.Fprintf(tw, "%s\t", grp)
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%s\n",
+			u.Messages, u.Input, u.Output, u.CacheWrite, u.CacheRead, usd)
+	}
+
+	fmt.Fprintf(tw, "%s\t", "PERIOD")
+	if group {
+		fmt.Fprintf(tw, "%s\t", groupHeader(opts.Query.GroupBy))
+	}
+	fmt.Fprintln(tw, "MESSAGES\tINPUT\tOUTPUT\tCACHE WRITE\tCACHE READ\tCOST")
+
+	var total cost.Usage
+	for _, row := range rows {
+		line(opts.Query.Period.Label(row.Start), groupLabel(row.Group), row.Usage, fmt.Sprintf("$%.2f", row.Cost))
+		total.Add(row.Usage)
+	}
+	line("TOTAL", "", total, fmt.Sprintf("$%.2f", total.Cost))
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, opts Options, rows []cost.ReportRow) error {
+	cw := csv.NewWriter(w)
+	group := opts.Query.GroupBy != cost.ByNone
+
+	header := []string{"period"}
+	if group {
+		header = append(header, string(opts.Query.GroupBy))
+	}
+	header = append(header, "messages", "input_tokens", "output_tokens", "cache_write_tokens", "cache_read_tokens", "cost_usd")
+	_ = cw.Write(header)
+
+	for _, row := range rows {
+		record := []string{opts.Query.Period.Label(row.Start)}
+		if group {
+			record = append(record, row.Group)
+		}
+		record = append(record,
+			strconv.Itoa(row.Messages),
+			strconv.Itoa(row.Input),
+			strconv.Itoa(row.Output),
+			strconv.Itoa(row.CacheWrite),
+			strconv.Itoa(row.CacheRead),
+			strconv.FormatFloat(row.Cost, 'f', 4, 64),
+		)
+		_ = cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonUsage is the JSON form of a row's usage.
+type jsonUsage struct {
+	Messages         int     `json:"messages"`
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	CacheWriteTokens int     `json:"cache_write_tokens"`
+	CacheReadTokens  int     `json:"cache_read_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+func toJSONUsage(u cost.Usage) jsonUsage {
+	return jsonUsage{
+		Messages:         u.Messages,
+		InputTokens:      u.Input,
+		OutputTokens:     u.Output,
+		CacheWriteTokens: u.CacheWrite,
+		CacheReadTokens:  u.CacheRead,
+		CostUSD:          math.Round(u.Cost*1e4) / 1e4,
+	}
+}
+
+// jsonRow is one JSON report row. Group is only set for grouped reports.
+type jsonRow struct {
+	Period string  `json:"period"`
+	Group  *string `json:"group,omitempty"`
+	jsonUsage
+}
+
+func writeJSON(w io.Writer, opts Options, rows []cost.ReportRow) error {
+	out := struct {
+		Since   string    `json:"since"`
+		Until   string    `json:"until"`
+		Period  string    `json:"period"`
+		GroupBy string    `json:"group_by,omitempty"`
+		Rows    []jsonRow `json:"rows"`
+		Total   jsonUsage `json:"total"`
+	}{
+		Since:   opts.Query.Since.Format(dateLayout),
+		Until:   opts.Query.Until.AddDate(0, 0, -1).Format(dateLayout),
+		Period:  string(opts.Query.Period),
+		GroupBy: string(opts.Query.GroupBy),
+		Rows:    make([]jsonRow, 0, len(rows)),
+	}
+
+	var total cost.Usage
+	for _, row := range rows {
+		jr := jsonRow{Period: opts.Query.Period.Label(row.Start), jsonUsage: toJSONUsage(row.Usage)}
+		if opts.Query.GroupBy != cost.ByNone {
+			jr.Group = &row.Group
+		}
+		out.Rows = append(out.Rows, jr)
+		total.Add(row.Usage)
+	}
+	out.Total = toJSONUsage(total)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// groupHeader is the table column header for g.
+func groupHeader(g cost.Grouping) string {
+	switch g {
+	case cost.ByProject:
+		return "PROJECT"
+	case cost.ByModel:
+		return "MODEL"
+	default:
+		return "BRANCH"
+	}
+}
+
+// groupLabel shows an empty group (a session outside any branch) as "-".
+func groupLabel(group string) string {
+	if group == "" {
+		return "-"
+	}
+	return group
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cost"
+)
+
+var now = time.Date(2026, 10, 17, 15, 0, 0, 0, time.Local)
+
+func TestParseArgs_Defaults(t *testing.T) {
+	opts, err := ParseArgs(nil, now, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Format != "table" || opts.Query.Period != cost.Day || opts.Query.GroupBy != cost.ByNone {
+		t.Errorf("unexpected defaults: %+v", opts)
+	}
+	if want := time.Date(2026, 9, 18, 0, 0, 0, 0, time.Local); !opts.Query.Since.Equal(want) {
+		t.Errorf("expected since %v, got %v", want, opts.Query.Since)
+	}
+	if want := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local); !opts.Query.Until.Equal(want) {
+		t.Errorf("expected until %v, got %v", want, opts.Query.Until)
+	}
+
+	opts, _ = ParseArgs([]string{"--period", "month"}, now, io.Discard)
+	if want := time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local); !opts.Query.Since.Equal(want) {
+		t.Errorf("expected a monthly report to start %v, got %v", want, opts.Query.Since)
+	}
+}
+
+func TestParseArgs_Range(t *testing.T) {
+	opts, err := ParseArgs([]string{"--since", "2026-09-01", "--until", "2026-09-30", "--group", "model", "--format", "csv"}, now, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Query.Since.Format(dateLayout) != "2026-09-01" || opts.Query.Until.Format(dateLayout) != "2026-10-01" {
+		t.Errorf("expected September inclusive, got %v to %v", opts.Query.Since, opts.Query.Until)
+	}
+	if opts.Query.GroupBy != cost.ByModel || opts.Format != "csv" {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
+
+func TestParseArgs_Errors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--period", "year"},
+		{"--group", "team"},
+		{"--format", "xml"},
+		{"--since", "yesterday"},
+		{"--since", "2026-10-02", "--until", "2026-10-01"},
+		{"extra"},
+	} {
+		if _, err := ParseArgs(args, now, io.Discard); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func sampleRows() []cost.ReportRow {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local)
+	return []cost.ReportRow{
+		{Start: day, Group: "claude-opus-4-6", Usage: cost.Usage{Messages: 2, Input: 1000, Output: 500, Cost: 1.25}},
+		{Start: day, Group: "claude-sonnet-5", Usage: cost.Usage{Messages: 1, Input: 300, CacheRead: 9000, Cost: 0.5}},
+	}
+}
+
+func sampleOptions(format string) Options {
+	return Options{
+		Format: format,
+		Query: cost.ReportQuery{
+			Since:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local),
+			Until:   time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local),
+			Period:  cost.Day,
+			GroupBy: cost.ByModel,
+		},
+	}
+}
+
+func TestWrite_Table(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleOptions("table"), sampleRows()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, 2 rows, and total, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "PERIOD") || !strings.Contains(lines[0], "MODEL") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "2026-10-16") || !strings.HasSuffix(lines[1], "$1.25") {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[3], "TOTAL") || !strings.Contains(lines[3], " 3 ") || !strings.HasSuffix(lines[3], "$1.75") {
+		t.Errorf("unexpected total: %q", lines[3])
+	}
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleOptions("csv"), sampleRows()); err != nil {
+		t.Fatal(err)
+	}
+	want := "period,model,messages,input_tokens,output_tokens,cache_write_tokens,cache_read_tokens,cost_usd\n" +
+		"2026-10-16,claude-opus-4-6,2,1000,500,0,0,1.2500\n" +
+		"2026-10-16,claude-sonnet-5,1,300,0,0,9000,0.5000\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleOptions("json"), sampleRows()); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Since   string `json:"since"`
+		Until   string `json:"until"`
+		GroupBy string `json:"group_by"`
+		Rows    []struct {
+			Period  string  `json:"period"`
+			Group   string  `json:"group"`
+			CostUSD float64 `json:"cost_usd"`
+		} `json:"rows"`
+		Total struct {
+			Messages int     `json:"messages"`
+			CostUSD  float64 `json:"cost_usd"`
+		} `json:"total"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if out.Since != "2026-10-01" || out.Until != "2026-10-17" || out.GroupBy != "model" {
+		t.Errorf("unexpected range: %+v", out)
+	}
+	if len(out.Rows) != 2 || out.Rows[1].Group != "claude-sonnet-5" || out.Rows[1].CostUSD != 0.5 {
+		t.Errorf("unexpected rows: %+v", out.Rows)
+	}
+	if out.Total.Messages != 3 || out.Total.CostUSD != 1.75 {
+		t.Errorf("unexpected total: %+v", out.Total)
+	}
+}
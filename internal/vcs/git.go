@@ -0,0 +1,92 @@
+package vcs
+
+import (
+	"sync"
+
+	"github.com/h2ik/claude-statusline/internal/git"
+)
+
+// GitRepo is the git backend. It also exposes the full git Snapshot for
+// components that render git-only detail.
+type GitRepo struct {
+	dir   string
+	opts  git.Options
+	cache *git.StateCache // nil runs git directly
+
+	once sync.Once
+	snap *git.Snapshot
+}
+
+// NewGit creates the git backend for dir. Lookups go through cache when it
+// is non-nil.
+func NewGit(dir string, opts git.Options, cache *git.StateCache) *GitRepo {
+	return &GitRepo{dir: dir, opts: opts, cache: cache}
+}
+
+// Kind returns Git.
+func (g *GitRepo) Kind() Kind {
+	return Git
+}
+
+// Snapshot returns the repository snapshot for the directory, taken on first
+// use. IsRepo is false when dir is not inside a git checkout.
+func (g *GitRepo) Snapshot() *git.Snapshot {
+	g.once.Do(func() {
+		if g.cache != nil {
+			g.snap = g.cache.Snapshot(g.dir, g.opts)
+			return
+		}
+		g.snap = git.TakeSnapshot(g.dir, g.opts)
+	})
+	return g.snap
+}
+
+// State derives the common state from the snapshot.
+func (g *GitRepo) State() *State {
+	snap := g.Snapshot()
+	st := &State{Branch: snap.Branch, Err: snap.StatusErr}
+	if snap.Head != "" {
+		st.Branch, st.ChangeID = "", snap.Head
+	}
+	if snap.StatusErr == nil {
+		st.Dirty = !snap.Status.Clean()
+		st.Conflicted = snap.Conflicted > 0
+	}
+	return st
+}
+
+// CountCommits counts matching commits, or 0 outside a git repository.
+func (g *GitRepo) CountCommits(q CommitQuery) (int, error) {
+	if !g.Snapshot().IsRepo {
+		return 0, nil
+	}
+	gq := git.CommitQuery{Since: q.Since, Authors: q.Authors, AllBranches: q.AllBranches}
+	if g.cache != nil {
+		return g.cache.CountCommits(g.dir, gq)
+	}
+	return git.CountCommits(g.dir, gq)
+}
+
+// SubmoduleStatus runs `git submodule status`, or reports a zero status
+// outside a git repository.
+func (g *GitRepo) SubmoduleStatus() (*SubmoduleStatus, error) {
+	if !g.Snapshot().IsRepo {
+		return &SubmoduleStatus{}, nil
+	}
+	var st *git.SubmoduleStatus
+	var err error
+	if g.cache != nil {
+		st, err = g.cache.SubmoduleStatus(g.dir)
+	} else {
+		st, err = git.GetSubmoduleStatus(g.dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &SubmoduleStatus{
+		Total:         st.Total,
+		Uninitialized: st.Uninitialized,
+		Modified:      st.Modified,
+		Conflicted:    st.Conflicted,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/git"
+)
+
+// gitRepo creates a repository with one commit and returns its directory.
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test User"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		runGit(t, dir, args...)
+	}
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestGitRepo_State(t *testing.T) {
+	dir := gitRepo(t)
+	g := NewGit(dir, git.Options{}, nil)
+
+	st := g.State()
+	if st.Branch != "main" || st.ChangeID != "" || st.Dirty || st.Err != nil {
+		t.Errorf("unexpected clean state: %+v", st)
+	}
+	if g.Snapshot() != g.Snapshot() {
+		t.Error("expected the snapshot to be taken once")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if st := NewGit(dir, git.Options{}, nil).State(); !st.Dirty {
+		t.Errorf("expected dirty state with an untracked file, got %+v", st)
+	}
+
+	runGit(t, dir, "checkout", "-q", "--detach")
+	st = NewGit(dir, git.Options{}, nil).State()
+	if st.Branch != "" || st.ChangeID == "" {
+		t.Errorf("expected a detached HEAD to report a change ID only, got %+v", st)
+	}
+}
+
+func TestGitRepo_OutsideRepo(t *testing.T) {
+	g := NewGit(t.TempDir(), git.Options{}, nil)
+
+	if n, err := g.CountCommits(CommitQuery{}); n != 0 || err != nil {
+		t.Errorf("expected 0 commits outside a repo, got %d (%v)", n, err)
+	}
+	if st, err := g.SubmoduleStatus(); err != nil || st.Total != 0 {
+		t.Errorf("expected zero submodule status outside a repo, got %+v (%v)", st, err)
+	}
+}
+
+func TestGitRepo_CountCommits(t *testing.T) {
+	dir := gitRepo(t)
+	n, err := NewGit(dir, git.Options{}, nil).CountCommits(CommitQuery{Since: git.StartOfDay(time.Now())})
+	if err != nil || n != 1 {
+		t.Errorf("expected 1 commit today, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,124 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/authors"
+	"github.com/h2ik/claude-statusline/internal/command"
+)
+
+// jjStateRevset selects the working-copy commit plus the nearest bookmarked
+// ancestors, whose bookmarks stand in for a branch when @ has none.
+const jjStateRevset = "@ | heads(::@- & bookmarks())"
+
+// jjStateTemplate prints one tab-separated line per commit: "@" for the
+// working copy, change ID, local bookmarks, and dirty/conflict flags.
+const jjStateTemplate = `if(current_working_copy, "@", "-") ++ "\t" ++ change_id.shortest(8) ++ "\t" ++ ` +
+	`local_bookmarks.map(|b| b.name()).join(",") ++ "\t" ++ ` +
+	`if(empty, "", "dirty") ++ "\t" ++ if(conflict, "conflict", "") ++ "\n"`
+
+// jjCommitTemplate prints each commit as the NUL-terminated author and
+// message pair authors.CountMatching expects.
+const jjCommitTemplate = `author.name() ++ " <" ++ author.email() ++ ">\0" ++ description ++ "\0"`
+
+// JujutsuRepo is the Jujutsu backend, for both colocated and native jj repos.
+// In jj the working copy is itself a commit (@), so "dirty" means @ is not
+// empty and the committed history starts at its parent.
+type JujutsuRepo struct {
+	dir string
+
+	once  sync.Once
+	state *State
+}
+
+// NewJujutsu creates the Jujutsu backend for dir.
+func NewJujutsu(dir string) *JujutsuRepo {
+	return &JujutsuRepo{dir: dir}
+}
+
+// Kind returns Jujutsu.
+func (j *JujutsuRepo) Kind() Kind {
+	return Jujutsu
+}
+
+// State runs `jj log` once for the working-copy commit and its nearest
+// bookmarks. Like any jj command, this snapshots working-copy changes first.
+func (j *JujutsuRepo) State() *State {
+	j.once.Do(func() {
+		output, err := j.jj("log", "-r", jjStateRevset, "-T", jjStateTemplate)
+		if err != nil {
+			j.state = &State{Err: err}
+			return
+		}
+		j.state = parseJujutsuState(output)
+	})
+	return j.state
+}
+
+// parseJujutsuState parses jjStateTemplate output. The working copy's own
+// bookmarks win over those of its ancestors.
+func parseJujutsuState(output []byte) *State {
+	st := &State{}
+	var inherited []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			continue
+		}
+		if fields[0] != "@" {
+			if fields[2] != "" {
+				inherited = append(inherited, fields[2])
+			}
+			continue
+		}
+		st.ChangeID = fields[1]
+		st.Branch = fields[2]
+		st.Dirty = fields[3] == "dirty"
+		st.Conflicted = fields[4] == "conflict"
+	}
+	if st.Branch == "" {
+		st.Branch = strings.Join(inherited, ",")
+	}
+	return st
+}
+
+// CountCommits counts matching commits by committer date among the
+// ancestors of @- (and of every local bookmark with q.AllBranches). The
+// working-copy commit itself is still in progress and never counts.
+func (j *JujutsuRepo) CountCommits(q CommitQuery) (int, error) {
+	patterns := authors.Resolve(q.Authors, func() string {
+		email, _ := j.jj("config", "get", "user.email")
+		return strings.TrimSpace(string(email))
+	})
+	if len(q.Authors) > 0 && len(patterns) == 0 {
+		return 0, nil
+	}
+
+	heads := "@-"
+	if q.AllBranches {
+		heads = "@- | bookmarks()"
+	}
+	revset := fmt.Sprintf("::(%s) & committer_date(after:%q)", heads, q.Since.Format(time.RFC3339))
+	output, err := j.jj("log", "--ignore-working-copy", "-r", revset, "-T", jjCommitTemplate)
+	if err != nil {
+		return 0, err
+	}
+	return authors.CountMatching(output, patterns), nil
+}
+
+// SubmoduleStatus reports a zero status: jj does not support submodules.
+func (j *JujutsuRepo) SubmoduleStatus() (*SubmoduleStatus, error) {
+	return &SubmoduleStatus{}, nil
+}
+
+// jj runs a jj subcommand with graph, color, and pager output disabled.
+func (j *JujutsuRepo) jj(args ...string) ([]byte, error) {
+	args = append(args, "--no-pager", "--color=never")
+	if args[0] == "log" {
+		args = append(args, "--no-graph")
+	}
+	return command.Run(j.dir, "jj", args...)
+}
@@ -0,0 +1,78 @@
+package vcs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/command"
+)
+
+func TestParseJujutsuState(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   State
+	}{
+		{
+			name:   "bookmark on working copy",
+			output: "@\tkxqpzmnv\tfeature\tdirty\t\n-\tzzzzzzzz\tmain\t\t\n",
+			want:   State{Branch: "feature", ChangeID: "kxqpzmnv", Dirty: true},
+		},
+		{
+			name:   "inherited bookmark",
+			output: "@\tkxqpzmnv\t\t\t\n-\tzzzzzzzz\tmain,release\t\t\n",
+			want:   State{Branch: "main,release", ChangeID: "kxqpzmnv"},
+		},
+		{
+			name:   "conflicted without bookmarks",
+			output: "@\tkxqpzmnv\t\tdirty\tconflict\n",
+			want:   State{ChangeID: "kxqpzmnv", Dirty: true, Conflicted: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseJujutsuState([]byte(tt.output)); *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJujutsuRepo_NoSubmodules(t *testing.T) {
+	st, err := NewJujutsu(t.TempDir()).SubmoduleStatus()
+	if err != nil || st.Total != 0 {
+		t.Errorf("expected zero submodule status, got %+v (%v)", st, err)
+	}
+}
+
+func TestJujutsuRepo_Live(t *testing.T) {
+	requireTool(t, "jj")
+	dir := t.TempDir()
+	t.Setenv("JJ_CONFIG", filepath.Join(dir, "jj-config.toml"))
+	t.Setenv("JJ_USER", "Test User")
+	t.Setenv("JJ_EMAIL", "test@test.com")
+	if _, err := command.Run(dir, "jj", "git", "init"); err != nil {
+		t.Fatalf("jj git init failed: %v", err)
+	}
+	if _, err := command.Run(dir, "jj", "commit", "-m", "first"); err != nil {
+		t.Fatalf("jj commit failed: %v", err)
+	}
+	if _, err := command.Run(dir, "jj", "bookmark", "create", "main", "-r", "@-"); err != nil {
+		t.Fatalf("jj bookmark create failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	j := NewJujutsu(dir)
+	st := j.State()
+	if st.Err != nil || st.Branch != "main" || st.ChangeID == "" || !st.Dirty {
+		t.Errorf("unexpected state: %+v", st)
+	}
+	n, err := j.CountCommits(CommitQuery{Since: time.Now().Add(-time.Hour)})
+	if err != nil || n != 1 {
+		t.Errorf("expected 1 commit today, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,176 @@
+package vcs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/h2ik/claude-statusline/internal/authors"
+	"github.com/h2ik/claude-statusline/internal/command"
+)
+
+// hgStateTemplate prints the working directory parent's short hash, named
+// branch, and active bookmark, tab-separated.
+const hgStateTemplate = `{node|short}\t{branch}\t{activebookmark}\n`
+
+// hgCommitTemplate prints each changeset as the NUL-terminated author and
+// message pair authors.CountMatching expects.
+const hgCommitTemplate = `{author}\x00{desc}\x00`
+
+// MercurialRepo is the Mercurial backend.
+type MercurialRepo struct {
+	dir  string
+	root string // directory holding .hg
+
+	once  sync.Once
+	state *State
+}
+
+// NewMercurial creates the Mercurial backend for dir inside the repository
+// rooted at root.
+func NewMercurial(dir, root string) *MercurialRepo {
+	return &MercurialRepo{dir: dir, root: root}
+}
+
+// Kind returns Mercurial.
+func (m *MercurialRepo) Kind() Kind {
+	return Mercurial
+}
+
+// State reads the working directory parent with `hg log -r .` and dirty
+// state with `hg status`; `hg resolve --list` runs only when there are
+// changes, since a conflicted merge always has some.
+func (m *MercurialRepo) State() *State {
+	m.once.Do(func() {
+		m.state = m.readState()
+	})
+	return m.state
+}
+
+func (m *MercurialRepo) readState() *State {
+	output, err := m.hg("log", "-r", ".", "-T", hgStateTemplate)
+	if err != nil {
+		return &State{Err: err}
+	}
+	st := parseMercurialState(output)
+
+	changes, err := m.hg("status", "--modified", "--added", "--removed", "--deleted")
+	if err != nil {
+		st.Err = err
+		return st
+	}
+	st.Dirty = strings.TrimSpace(string(changes)) != ""
+	if st.Dirty {
+		unresolved, err := m.hg("resolve", "--list")
+		if err == nil {
+			st.Conflicted = hasUnresolved(unresolved)
+		}
+	}
+	return st
+}
+
+// parseMercurialState parses hgStateTemplate output. The active bookmark,
+// when there is one, is a better label than the named branch.
+func parseMercurialState(output []byte) *State {
+	fields := strings.Split(strings.TrimRight(string(output), "\n"), "\t")
+	st := &State{ChangeID: fields[0]}
+	if len(fields) == 3 {
+		st.Branch = fields[1]
+		if fields[2] != "" {
+			st.Branch = fields[2]
+		}
+	}
+	return st
+}
+
+// hasUnresolved reports whether `hg resolve --list` output has a "U" line.
+func hasUnresolved(output []byte) bool {
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "U ") {
+			return true
+		}
+	}
+	return false
+}
+
+// CountCommits counts matching changesets among the ancestors of the working
+// directory parent (or all changesets with q.AllBranches). Mercurial keeps a
+// single date per changeset, which is what Since is compared against.
+func (m *MercurialRepo) CountCommits(q CommitQuery) (int, error) {
+	patterns := authors.Resolve(q.Authors, func() string {
+		username, _ := m.hg("config", "ui.username")
+		return strings.TrimSpace(string(username))
+	})
+	if len(q.Authors) > 0 && len(patterns) == 0 {
+		return 0, nil
+	}
+
+	heads := "ancestors(.)"
+	if q.AllBranches {
+		heads = "all()"
+	}
+	revset := fmt.Sprintf("%s and date('>%d 0')", heads, q.Since.Unix())
+	output, err := m.hg("log", "-r", revset, "-T", hgCommitTemplate)
+	if err != nil {
+		return 0, err
+	}
+	return authors.CountMatching(output, patterns), nil
+}
+
+// SubmoduleStatus counts the subrepos declared in .hgsub and how many are
+// not checked out yet, reading the files directly. Whether a subrepo sits at
+// its recorded revision is not checked.
+func (m *MercurialRepo) SubmoduleStatus() (*SubmoduleStatus, error) {
+	paths, err := readHgsub(filepath.Join(m.root, ".hgsub"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SubmoduleStatus{}, nil
+		}
+		return nil, err
+	}
+	st := &SubmoduleStatus{Total: len(paths)}
+	for _, p := range paths {
+		if !isDir(filepath.Join(m.root, p)) {
+			st.Uninitialized++
+		}
+	}
+	return st, nil
+}
+
+// readHgsub returns the subrepo paths of a .hgsub file: "path = source"
+// lines outside the [subpaths] remapping section.
+func readHgsub(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	inSubpaths := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+			continue
+		case line[0] == '[':
+			inSubpaths = line == "[subpaths]"
+			continue
+		case inSubpaths:
+			continue
+		}
+		if p, _, ok := strings.Cut(line, "="); ok {
+			paths = append(paths, strings.TrimSpace(p))
+		}
+	}
+	return paths, scanner.Err()
+}
+
+// hg runs an hg subcommand without a pager.
+func (m *MercurialRepo) hg(args ...string) ([]byte, error) {
+	return command.Run(m.dir, "hg", append(args, "--pager=never")...)
+}
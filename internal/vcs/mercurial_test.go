@@ -0,0 +1,88 @@
+package vcs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/command"
+)
+
+func TestParseMercurialState(t *testing.T) {
+	tests := []struct {
+		output string
+		want   State
+	}{
+		{"1a2b3c4d5e6f\tdefault\t\n", State{ChangeID: "1a2b3c4d5e6f", Branch: "default"}},
+		{"1a2b3c4d5e6f\tdefault\tfeature\n", State{ChangeID: "1a2b3c4d5e6f", Branch: "feature"}},
+	}
+	for _, tt := range tests {
+		if got := parseMercurialState([]byte(tt.output)); *got != tt.want {
+			t.Errorf("parseMercurialState(%q) = %+v, want %+v", tt.output, *got, tt.want)
+		}
+	}
+}
+
+func TestHasUnresolved(t *testing.T) {
+	if !hasUnresolved([]byte("R a.txt\nU b.txt\n")) {
+		t.Error("expected an unresolved file to be found")
+	}
+	if hasUnresolved([]byte("R a.txt\n")) {
+		t.Error("expected no unresolved files")
+	}
+}
+
+func TestMercurialRepo_SubmoduleStatus(t *testing.T) {
+	root := t.TempDir()
+	hgsub := "# subrepos\nlibs/a = https://example.com/a\nlibs/b = [git]https://example.com/b\n\n[subpaths]\nhttps://example.com/(.*) = https://mirror/\\1\n"
+	if err := os.WriteFile(filepath.Join(root, ".hgsub"), []byte(hgsub), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mkdirs(t, root, "libs/a")
+
+	st, err := NewMercurial(root, root).SubmoduleStatus()
+	if err != nil {
+		t.Fatalf("SubmoduleStatus failed: %v", err)
+	}
+	if st.Total != 2 || st.Uninitialized != 1 {
+		t.Errorf("expected 2 subrepos with 1 uninitialized, got %+v", st)
+	}
+
+	st, err = NewMercurial(t.TempDir(), t.TempDir()).SubmoduleStatus()
+	if err != nil || st.Total != 0 {
+		t.Errorf("expected zero status without .hgsub, got %+v (%v)", st, err)
+	}
+}
+
+func TestMercurialRepo_Live(t *testing.T) {
+	requireTool(t, "hg")
+	dir := t.TempDir()
+	t.Setenv("HGRCPATH", "")
+	t.Setenv("HGUSER", "Test User <test@test.com>")
+	if _, err := command.Run(dir, "hg", "init"); err != nil {
+		t.Fatalf("hg init failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := command.Run(dir, "hg", "commit", "-A", "-m", "first"); err != nil {
+		t.Fatalf("hg commit failed: %v", err)
+	}
+
+	m := NewMercurial(dir, dir)
+	if st := m.State(); st.Err != nil || st.Branch != "default" || st.ChangeID == "" || st.Dirty {
+		t.Errorf("unexpected clean state: %+v", st)
+	}
+	n, err := m.CountCommits(CommitQuery{Since: time.Now().Add(-time.Hour)})
+	if err != nil || n != 1 {
+		t.Errorf("expected 1 commit today, got %d (%v)", n, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("y"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if st := NewMercurial(dir, dir).State(); !st.Dirty {
+		t.Errorf("expected dirty state after an edit, got %+v", st)
+	}
+}
@@ -0,0 +1,130 @@
+// Package vcs puts git, Jujutsu, and Mercurial working copies behind one
+// interface, so components that only need the basics -- what is checked out,
+// whether it is dirty, how many commits were made -- work in any of them.
+// Git-specific detail such as porcelain status counts stays in package git.
+package vcs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/command"
+)
+
+// Kind names a version control system.
+type Kind string
+
+const (
+	Git       Kind = "git"
+	Jujutsu   Kind = "jj"
+	Mercurial Kind = "hg"
+)
+
+// Repo is a working copy managed by one of the supported systems. All
+// methods are safe for concurrent use.
+type Repo interface {
+	// Kind reports which system manages the working copy.
+	Kind() Kind
+
+	// State summarizes what is checked out and whether it has changes.
+	State() *State
+
+	// CountCommits counts the commits matching q that are ancestors of the
+	// working copy's parent, or of any branch/bookmark with q.AllBranches.
+	CountCommits(q CommitQuery) (int, error)
+
+	// SubmoduleStatus summarizes nested repositories: git submodules or
+	// Mercurial subrepos. Systems without them report a zero status.
+	SubmoduleStatus() (*SubmoduleStatus, error)
+}
+
+// CommitQuery selects the commits Repo.CountCommits counts.
+type CommitQuery struct {
+	// Since is the start of the window; earlier commits are not counted.
+	Since time.Time
+
+	// Authors restricts the count to commits whose author or any
+	// Co-authored-by trailer matches one of these patterns (see
+	// authors.Resolve). "me" stands for the system's configured identity.
+	// Empty counts every author.
+	Authors []string
+
+	// AllBranches counts commits reachable from any branch or bookmark
+	// instead of only the working copy's parent.
+	AllBranches bool
+}
+
+// SubmoduleStatus counts nested repositories by state.
+type SubmoduleStatus struct {
+	Total         int
+	Uninitialized int // not checked out
+	Modified      int // checked out at another revision than recorded
+	Conflicted    int // merge conflicts in the recorded revision
+}
+
+// InSync returns the number of nested repositories checked out at their
+// recorded revision.
+func (s *SubmoduleStatus) InSync() int {
+	return s.Total - s.Uninitialized - s.Modified - s.Conflicted
+}
+
+// State is the part of a working copy's state every system can report.
+type State struct {
+	// Branch is the git branch, Mercurial active bookmark or named branch,
+	// or the Jujutsu bookmark(s) at or nearest below the working copy.
+	// Empty for a detached git HEAD or an unbookmarked jj change.
+	Branch string
+
+	// ChangeID identifies the working copy: the short Jujutsu change ID,
+	// the short Mercurial changeset hash, or the tag or short hash of a
+	// detached git HEAD.
+	ChangeID string
+
+	Dirty      bool
+	Conflicted bool
+
+	// Err is set when the state could not be read; Dirty and Conflicted are
+	// then unknown rather than false.
+	Err error
+}
+
+// TimedOut reports whether reading the state was killed for exceeding the
+// command timeout.
+func (s *State) TimedOut() bool {
+	return errors.Is(s.Err, command.ErrTimeout)
+}
+
+// Detect walks up from dir to the nearest directory holding a .jj, .git, or
+// .hg marker and returns that system and directory. A colocated Jujutsu repo,
+// which has both .jj and .git, is reported as Jujutsu since jj manages its
+// working copy. Detect returns "" outside any repository.
+func Detect(dir string) (Kind, string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+	for {
+		if isDir(filepath.Join(dir, ".jj")) {
+			return Jujutsu, dir
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return Git, dir
+		}
+		if isDir(filepath.Join(dir, ".hg")) {
+			return Mercurial, dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
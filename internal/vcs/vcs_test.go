@@ -0,0 +1,74 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// mkdirs creates each path under root.
+func mkdirs(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		if err := os.MkdirAll(filepath.Join(root, p), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// requireTool skips the test when the named binary is not installed.
+func requireTool(t *testing.T, name string) {
+	t.Helper()
+	if _, err := exec.LookPath(name); err != nil {
+		t.Skipf("%s not installed", name)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	mkdirs(t, root,
+		"git/.git", "git/sub/dir",
+		"colocated/.git", "colocated/.jj",
+		"hg/.hg", "hg/nested-jj/.jj",
+		"plain",
+	)
+
+	tests := []struct {
+		dir      string
+		wantKind Kind
+		wantRoot string
+	}{
+		{"git/sub/dir", Git, "git"},
+		{"colocated", Jujutsu, "colocated"},
+		{"hg", Mercurial, "hg"},
+		{"hg/nested-jj", Jujutsu, "hg/nested-jj"},
+		{"plain", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			kind, dir := Detect(filepath.Join(root, tt.dir))
+			if kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", kind, tt.wantKind)
+			}
+			wantRoot := ""
+			if tt.wantRoot != "" {
+				wantRoot = filepath.Join(root, tt.wantRoot)
+			}
+			if dir != wantRoot {
+				t.Errorf("root = %q, want %q", dir, wantRoot)
+			}
+		})
+	}
+}
+
+func TestDetect_GitFile(t *testing.T) {
+	// Linked worktrees and submodules have a .git file, not a directory.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: /elsewhere\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if kind, _ := Detect(dir); kind != Git {
+		t.Errorf("expected a .git file to be detected as git, got %q", kind)
+	}
+}
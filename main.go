@@ -2,23 +2,28 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/h2ik/claude-statusline/internal/cache"
-	"github.com/h2ik/claude-statusline/internal/claude"
+	"github.com/h2ik/claude-statusline/internal/command"
 	"github.com/h2ik/claude-statusline/internal/component"
 	"github.com/h2ik/claude-statusline/internal/components"
 	"github.com/h2ik/claude-statusline/internal/config"
 	"github.com/h2ik/claude-statusline/internal/cost"
+	"github.com/h2ik/claude-statusline/internal/git"
 	"github.com/h2ik/claude-statusline/internal/icons"
 	"github.com/h2ik/claude-statusline/internal/input"
 	"github.com/h2ik/claude-statusline/internal/render"
+	"github.com/h2ik/claude-statusline/internal/report"
 
 	"golang.org/x/term"
 )
@@ -41,8 +46,22 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Read JSON from stdin
-	in, err := input.ParseInput(os.Stdin)
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		os.Exit(runReport(os.Args[2:]))
+	}
+
+	// --refresh is internal: spawned by spawnRefresh to recompute components
+	// that missed their deadline, with the original stdin JSON as its input.
+	refreshing := len(os.Args) > 2 && os.Args[1] == "--refresh"
+
+	// Read JSON from stdin, keeping the raw bytes so a background refresh can
+	// be handed exactly the same input.
+	raw, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read input: %v\n", err)
+		os.Exit(1)
+	}
+	in, err := input.ParseInput(bytes.NewReader(raw))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to parse input: %v\n", err)
 		os.Exit(1)
@@ -50,7 +69,7 @@ func main() {
 
 	// Initialize infrastructure
 	homeDir, _ := os.UserHomeDir()
-	claudeSettings, _ := claude.LoadSettings(filepath.Join(homeDir, ".claude", "settings.json"))
+	settingsPath := filepath.Join(homeDir, ".claude", "settings.json")
 	cacheDir := filepath.Join(homeDir, ".cache", "claude-statusline")
 	costDir := filepath.Join(homeDir, ".claude", "statusline", "costs")
 	projectsDir := filepath.Join(homeDir, ".claude", "projects")
@@ -63,6 +82,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	for _, err := range cfg.WindowErrors() {
+		fmt.Fprintf(os.Stderr, "%v, using the default window\n", err)
+	}
+
 	// Resolve theme from config
 	theme, ok := render.ThemeByName(cfg.Layout.Theme)
 	if !ok {
@@ -70,11 +93,15 @@ func main() {
 	}
 
 	c := cache.New(cacheDir)
-	_ = c.Prune(30 * 24 * time.Hour)
+	if !refreshing {
+		_ = c.Prune(30 * 24 * time.Hour)
+	}
 	r := render.New(&theme)
 	h := cost.NewHistory(filepath.Join(costDir, "history.jsonl"))
 	scanner := cost.NewTranscriptScanner(projectsDir, c)
 
+	installPricing(cfg)
+
 	// Create icon set from config
 	ic := icons.New(cfg.Layout.IconStyle)
 
@@ -83,19 +110,29 @@ func main() {
 
 	// Line 1 components
 	registry.Register(components.NewRepoInfo(r, cfg, ic))
+	registry.Register(components.NewGitRemote(r, ic))
+	registry.Register(components.NewWorktrees(r, ic))
 
 	// Line 2 components
 	registry.Register(components.NewModelInfo(r, ic))
-	registry.Register(components.NewBedrockModel(r, c, cfg, claudeSettings, ic))
-	registry.Register(components.NewCommits(r, ic))
+	registry.Register(components.NewBedrockModel(r, c, cfg, nil, ic))
+	registry.Register(components.NewCommits(r, cfg, ic))
+	registry.Register(components.NewLastCommit(r, cfg, ic))
 	registry.Register(components.NewSubmodules(r, ic))
 	registry.Register(components.NewVersionInfo(r, c))
 	registry.Register(components.NewTimeDisplay(r, ic))
 
 	// Line 3 components
-	registry.Register(components.NewCostMonthly(r, scanner, ic))
-	registry.Register(components.NewCostWeekly(r, scanner, ic))
-	registry.Register(components.NewCostDaily(r, scanner, ic))
+	registry.Register(components.NewCostMonthly(r, scanner, cfg, ic))
+	registry.Register(components.NewCostWeekly(r, scanner, cfg, ic))
+	registry.Register(components.NewCostDaily(r, scanner, cfg, ic))
+	for _, name := range cfg.ComponentsOfType("cost_period") {
+		registry.Register(components.NewCostPeriod(r, scanner, cfg, ic, name))
+	}
+	registry.Register(components.NewCostProject(r, scanner, ic))
+	registry.Register(components.NewCostModels(r, scanner, cfg, ic))
+	registry.Register(components.NewBudget(r, scanner, cfg, ic))
+	registry.Register(components.NewCostForecast(r, scanner, cfg, ic))
 	registry.Register(components.NewCostLive(r, h, ic))
 	registry.Register(components.NewContextWindow(r, cfg, ic))
 	registry.Register(components.NewSessionMode(r, ic))
@@ -105,6 +142,35 @@ func main() {
 	registry.Register(components.NewCacheEfficiency(r, ic))
 	registry.Register(components.NewBlockProjection(r, ic))
 	registry.Register(components.NewCodeProductivity(r, cfg, ic))
+	registry.Register(components.NewDiffStat(r, cfg, ic))
+
+	// One Context per invocation: components share its git snapshot, cost
+	// scans, and Claude settings instead of recomputing them.
+	ctx := component.NewContext(in, settingsPath)
+	if d, ok := cfg.GitTimeout(); ok {
+		command.SetTimeout(d)
+	}
+	ctx.SetGitOptions(git.Options{
+		LargeRepoPaths:     cfg.LargeRepoPaths(),
+		LargeRepoIndexSize: cfg.LargeRepoIndexSize(),
+	})
+	ctx.SetGitCache(git.NewStateCache(c))
+
+	// Last-good outputs are scoped to the session and workspace so a stale
+	// value is only ever shown where it was produced.
+	scope := in.SessionID + ":" + in.Workspace.CurrentDir
+	store := cache.NewLastGood(c, scope)
+
+	if refreshing {
+		runRefresh(registry, store, ctx, strings.Split(os.Args[2], ","))
+		endRefresh()
+		return
+	}
+
+	registry.SetStale(store,
+		func(s string) string { return r.Dimmed(render.StripANSI(s)) },
+		func(names []string) { spawnRefresh(c, scope, raw, names) },
+	)
 
 	// Select rendering style
 	switch cfg.Layout.Style {
@@ -125,11 +191,18 @@ func main() {
 	//   4. Default to 80
 	termWidth := detectTerminalWidth() - cfg.Layout.Padding
 
-	// Render each line
+	// Render every component in the layout concurrently, then assemble lines
+	// from the results so slow components don't hold up the others.
+	registry.SetTimeouts(cfg.ComponentTimeout(""), cfg.RenderBudget())
+	for name := range cfg.Components {
+		registry.SetComponentTimeout(name, cfg.ComponentTimeout(name))
+	}
+	rendered := registry.RenderAllWithContext(ctx, cfg.ComponentNames())
+
 	var lineData []render.LineData
 	for _, line := range cfg.Layout.Lines {
-		leftNames, leftContent := registry.RenderNamedLine(in, line.Left)
-		rightNames, rightContent := registry.RenderNamedLine(in, line.Right)
+		leftNames, leftContent := rendered.Line(line.Left)
+		rightNames, rightContent := rendered.Line(line.Right)
 		if len(leftContent) > 0 || len(rightContent) > 0 {
 			lineData = append(lineData, render.LineData{
 				Left:       leftContent,
@@ -144,6 +217,34 @@ func main() {
 	_, _ = fmt.Fprint(os.Stdout, output)
 }
 
+// installPricing installs the [pricing] overrides from cfg. It must run
+// before any cost is computed, since the overrides also key the transcript
+// cost cache.
+func installPricing(cfg *config.Config) {
+	pricing, err := cfg.PricingOverrides()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load pricing file: %v\n", err)
+	}
+	cost.SetOverrides(pricing.Overrides())
+}
+
+// runReport runs `claude-statusline report` over the transcripts in
+// ~/.claude/projects, priced with the configured overrides, and returns the
+// exit code.
+func runReport(args []string) int {
+	homeDir, _ := os.UserHomeDir()
+	cfg, err := config.Load(filepath.Join(homeDir, ".claude", "statusline", "config.toml"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	installPricing(cfg)
+
+	c := cache.New(filepath.Join(homeDir, ".cache", "claude-statusline"))
+	scanner := cost.NewTranscriptScanner(filepath.Join(homeDir, ".claude", "projects"), c)
+	return report.Run(args, scanner, os.Stdout, os.Stderr)
+}
+
 // bustCache prompts the user to confirm, then removes all cached data.
 // Run manually from a terminal (not during normal stdin-driven rendering).
 func bustCache() {
@@ -177,6 +278,76 @@ func bustCache() {
 	fmt.Printf("Removed %d cache file(s).\n", removed)
 }
 
+// refreshLockTTL keeps back-to-back renders from each spawning their own
+// background refresh while one is already running for the same components.
+const refreshLockTTL = 30 * time.Second
+
+// refreshBudget bounds a background refresh render. It is well inside
+// refreshLockTTL, so a refresh whose components hang has given up and exited
+// before its lock expires and the next render may spawn another.
+var refreshBudget = 20 * time.Second
+
+// runRefresh is the body of --refresh: it renders the named components under
+// refreshBudget so their own caches and last-good outputs are warm for the
+// next foreground render. Nothing is printed. Components still running at
+// the deadline are abandoned; the caller exits right after.
+func runRefresh(registry *component.Registry, store component.StaleStore, ctx *component.Context, names []string) {
+	registry.SetStale(store, nil, nil)
+	registry.SetTimeouts(0, refreshBudget)
+	registry.RenderAllWithContext(ctx, names)
+}
+
+// takeRefreshLock claims the refresh of the components keyed by key, and
+// reports false when another refresh already holds it.
+func takeRefreshLock(c *cache.Cache, key string) bool {
+	if _, err := c.Get(key, refreshLockTTL); err == nil {
+		return false
+	}
+	_ = c.Set(key, nil, refreshLockTTL)
+	return true
+}
+
+// spawnRefresh starts a detached copy of this binary in --refresh mode for the
+// named components, feeding it the same stdin JSON via a temp file. It returns
+// as soon as the child has started; the child outlives this process.
+func spawnRefresh(c *cache.Cache, scope string, raw []byte, names []string) {
+	sort.Strings(names)
+	joined := strings.Join(names, ",")
+	if !takeRefreshLock(c, "refresh-lock:"+scope+":"+joined) {
+		return
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	// A pipe would be cut off when we exit, so hand the child a real file.
+	// Unlinking it after Start is fine: the child keeps its open descriptor.
+	f, err := os.CreateTemp("", "claude-statusline-refresh-*.json")
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	if _, err := f.Write(raw); err != nil {
+		return
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	cmd := exec.Command(exe, "--refresh", joined)
+	cmd.Stdin = f
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	_ = cmd.Process.Release()
+}
+
 // detectTerminalWidth tries multiple strategies to determine the terminal width.
 func detectTerminalWidth() int {
 	// Try stderr then stdout (stdin is consumed by JSON input)
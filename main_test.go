@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/h2ik/claude-statusline/internal/cache"
+	"github.com/h2ik/claude-statusline/internal/component"
+	"github.com/h2ik/claude-statusline/internal/input"
+)
+
+// hangingComponent never finishes rendering, like a stuck aws or git call.
+type hangingComponent struct{ release chan struct{} }
+
+func (c *hangingComponent) Name() string { return "hanging" }
+
+func (c *hangingComponent) Render(*input.StatusLineInput) string {
+	<-c.release
+	return ""
+}
+
+func TestRefreshBudget_InsideLockTTL(t *testing.T) {
+	if refreshBudget <= 0 || refreshBudget >= refreshLockTTL {
+		t.Errorf("expected refreshBudget %v to be positive and below refreshLockTTL %v", refreshBudget, refreshLockTTL)
+	}
+}
+
+func TestRunRefresh_SlowComponentCannotStartSecondRefresh(t *testing.T) {
+	defer func(d time.Duration) { refreshBudget = d }(refreshBudget)
+	refreshBudget = 50 * time.Millisecond
+
+	c := cache.New(t.TempDir())
+	const key = "refresh-lock:test:hanging"
+	if !takeRefreshLock(c, key) {
+		t.Fatal("expected the first refresh to take the lock")
+	}
+
+	hanging := &hangingComponent{release: make(chan struct{})}
+	defer close(hanging.release)
+	registry := component.NewRegistry()
+	registry.Register(hanging)
+	in := &input.StatusLineInput{}
+
+	done := make(chan struct{})
+	go func() {
+		runRefresh(registry, cache.NewLastGood(c, "test"), component.NewContext(in, ""), []string{"hanging"})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the refresh to give up on a hanging component")
+	}
+
+	// The refresh has ended while its lock still holds, so no render could
+	// have spawned a second one alongside it.
+	if takeRefreshLock(c, key) {
+		t.Error("expected the lock to outlast the refresh")
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+import "os/exec"
+
+// detach is a no-op where there are no Unix sessions; the child still
+// outlives this process once its handle is released.
+func detach(cmd *exec.Cmd) {}
+
+// endRefresh is a no-op without process groups; the refresh's own goroutines
+// end when it exits.
+func endRefresh() {}
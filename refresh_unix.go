@@ -0,0 +1,25 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts cmd in its own session so it is not killed along with the
+// statusline's process group when Claude Code reaps it.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// endRefresh kills what a refresh leaves behind in its process group, such
+// as an aws or claude call still hanging past refreshBudget, and the refresh
+// itself with it. It only does so as the group leader detach made it, so a
+// refresh started by hand never signals its caller's group.
+func endRefresh() {
+	if syscall.Getpgrp() == os.Getpid() {
+		_ = syscall.Kill(0, syscall.SIGKILL)
+	}
+}